@@ -0,0 +1,459 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mrrizkin/omniscan/app/controllers/types"
+	"github.com/mrrizkin/omniscan/app/models"
+	"github.com/mrrizkin/omniscan/app/providers/app"
+	"github.com/mrrizkin/omniscan/app/providers/logger"
+	"github.com/mrrizkin/omniscan/app/services/category"
+)
+
+type CategoryController struct {
+	*app.App
+
+	log *logger.Logger
+
+	categoryService *category.CategoryService
+}
+
+func (*CategoryController) Construct() interface{} {
+	return func(
+		app *app.App,
+		log *logger.Logger,
+
+		categoryService *category.CategoryService,
+	) (*CategoryController, error) {
+		return &CategoryController{
+			App: app,
+			log: log,
+
+			categoryService: categoryService,
+		}, nil
+	}
+}
+
+// CategoryFindAll godoc
+//
+//	@Summary		Get all categories
+//	@Description	Retrieve every transaction category
+//	@Tags			Categories
+//	@Produce		json
+//	@Success		200	{object}	types.Response{data=[]models.Category}	"Successfully retrieved categories"
+//	@Failure		500	{object}	validator.GlobalErrorResponse			"Internal server error"
+//	@Router			/categories [get]
+func (c *CategoryController) CategoryFindAll(ctx *fiber.Ctx) error {
+	categories, err := c.categoryService.FindAll()
+	if err != nil {
+		c.log.Error("failed to get categories", "err", err)
+		return &fiber.Error{
+			Code:    fiber.StatusInternalServerError,
+			Message: fmt.Sprintf("failed to get categories: %s", err),
+		}
+	}
+
+	return ctx.JSON(types.Response{
+		Status:  "success",
+		Message: "categories retrieved successfully",
+		Data:    categories,
+	})
+}
+
+// CategoryFindByID godoc
+//
+//	@Summary		Get a category by ID
+//	@Description	Retrieve a transaction category by its ID
+//	@Tags			Categories
+//	@Produce		json
+//	@Param			id	path		int										true	"Category ID"
+//	@Success		200	{object}	types.Response{data=models.Category}	"Successfully retrieved category"
+//	@Failure		400	{object}	validator.GlobalErrorResponse			"Bad request"
+//	@Failure		404	{object}	validator.GlobalErrorResponse			"Category not found"
+//	@Failure		500	{object}	validator.GlobalErrorResponse			"Internal server error"
+//	@Router			/categories/{id} [get]
+func (c *CategoryController) CategoryFindByID(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return &fiber.Error{
+			Code:    fiber.StatusBadRequest,
+			Message: "invalid id",
+		}
+	}
+
+	category, err := c.categoryService.FindByID(uint(id))
+	if err != nil {
+		return c.categoryError("failed to get category", err)
+	}
+
+	return ctx.JSON(types.Response{
+		Status:  "success",
+		Message: "Category retrieved successfully",
+		Data:    category,
+	})
+}
+
+// CategoryCreate godoc
+//
+//	@Summary		Create a new category
+//	@Description	Create a transaction category, the slug is what rules and transactions refer to
+//	@Tags			Categories
+//	@Accept			json
+//	@Produce		json
+//	@Param			category	body		models.Category							true	"Category information"
+//	@Success		200			{object}	types.Response{data=models.Category}	"Successfully created category"
+//	@Failure		400			{object}	validator.GlobalErrorResponse			"Bad request"
+//	@Failure		500			{object}	validator.GlobalErrorResponse			"Internal server error"
+//	@Router			/categories [post]
+func (c *CategoryController) CategoryCreate(ctx *fiber.Ctx) error {
+	payload := new(models.Category)
+	err := c.ParseBodyAndValidate(ctx, payload)
+	if err != nil {
+		return err
+	}
+
+	category, err := c.categoryService.Create(payload)
+	if err != nil {
+		return c.categoryError("failed to create category", err)
+	}
+
+	return ctx.JSON(types.Response{
+		Status:  "success",
+		Message: "Category created successfully",
+		Data:    category,
+	})
+}
+
+// CategoryUpdate godoc
+//
+//	@Summary		Update a category
+//	@Description	Update the name and description of a category, the slug can't be changed
+//	@Tags			Categories
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		int										true	"Category ID"
+//	@Param			category	body		models.Category							true	"Updated category information"
+//	@Success		200			{object}	types.Response{data=models.Category}	"Successfully updated category"
+//	@Failure		400			{object}	validator.GlobalErrorResponse			"Bad request"
+//	@Failure		404			{object}	validator.GlobalErrorResponse			"Category not found"
+//	@Failure		500			{object}	validator.GlobalErrorResponse			"Internal server error"
+//	@Router			/categories/{id} [put]
+func (c *CategoryController) CategoryUpdate(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return &fiber.Error{
+			Code:    fiber.StatusBadRequest,
+			Message: "invalid id",
+		}
+	}
+
+	payload := new(models.Category)
+	err = c.ParseBodyAndValidate(ctx, payload)
+	if err != nil {
+		return err
+	}
+
+	category, err := c.categoryService.Update(uint(id), payload)
+	if err != nil {
+		return c.categoryError("failed to update category", err)
+	}
+
+	return ctx.JSON(types.Response{
+		Status:  "success",
+		Message: "Category updated successfully",
+		Data:    category,
+	})
+}
+
+// CategoryDelete godoc
+//
+//	@Summary		Delete a category
+//	@Description	Delete a category that no rule uses
+//	@Tags			Categories
+//	@Produce		json
+//	@Param			id	path		int								true	"Category ID"
+//	@Success		200	{object}	types.Response{}				"Successfully deleted category"
+//	@Failure		400	{object}	validator.GlobalErrorResponse	"Bad request"
+//	@Failure		404	{object}	validator.GlobalErrorResponse	"Category not found"
+//	@Failure		500	{object}	validator.GlobalErrorResponse	"Internal server error"
+//	@Router			/categories/{id} [delete]
+func (c *CategoryController) CategoryDelete(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return &fiber.Error{
+			Code:    fiber.StatusBadRequest,
+			Message: "invalid id",
+		}
+	}
+
+	err = c.categoryService.Delete(uint(id))
+	if err != nil {
+		return c.categoryError("failed to delete category", err)
+	}
+
+	return ctx.JSON(types.Response{
+		Status:  "success",
+		Message: "Category deleted successfully",
+	})
+}
+
+// CategoryRuleFindAll godoc
+//
+//	@Summary		Get all category rules
+//	@Description	Retrieve every categorization rule in the order they are applied
+//	@Tags			Category Rules
+//	@Produce		json
+//	@Success		200	{object}	types.Response{data=[]models.CategoryRule}	"Successfully retrieved category rules"
+//	@Failure		500	{object}	validator.GlobalErrorResponse				"Internal server error"
+//	@Router			/category-rules [get]
+func (c *CategoryController) CategoryRuleFindAll(ctx *fiber.Ctx) error {
+	rules, err := c.categoryService.FindAllRules()
+	if err != nil {
+		c.log.Error("failed to get category rules", "err", err)
+		return &fiber.Error{
+			Code:    fiber.StatusInternalServerError,
+			Message: fmt.Sprintf("failed to get category rules: %s", err),
+		}
+	}
+
+	return ctx.JSON(types.Response{
+		Status:  "success",
+		Message: "category rules retrieved successfully",
+		Data:    rules,
+	})
+}
+
+// CategoryRuleFindByID godoc
+//
+//	@Summary		Get a category rule by ID
+//	@Description	Retrieve a categorization rule by its ID
+//	@Tags			Category Rules
+//	@Produce		json
+//	@Param			id	path		int											true	"Category rule ID"
+//	@Success		200	{object}	types.Response{data=models.CategoryRule}	"Successfully retrieved category rule"
+//	@Failure		400	{object}	validator.GlobalErrorResponse				"Bad request"
+//	@Failure		404	{object}	validator.GlobalErrorResponse				"Category rule not found"
+//	@Failure		500	{object}	validator.GlobalErrorResponse				"Internal server error"
+//	@Router			/category-rules/{id} [get]
+func (c *CategoryController) CategoryRuleFindByID(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return &fiber.Error{
+			Code:    fiber.StatusBadRequest,
+			Message: "invalid id",
+		}
+	}
+
+	rule, err := c.categoryService.FindRuleByID(uint(id))
+	if err != nil {
+		return c.categoryError("failed to get category rule", err)
+	}
+
+	return ctx.JSON(types.Response{
+		Status:  "success",
+		Message: "Category rule retrieved successfully",
+		Data:    rule,
+	})
+}
+
+// CategoryRuleCreate godoc
+//
+//	@Summary		Create a new category rule
+//	@Description	Create a categorization rule, once any rule exists the stored rules replace the configured ones
+//	@Tags			Category Rules
+//	@Accept			json
+//	@Produce		json
+//	@Param			rule	body		models.CategoryRule							true	"Category rule"
+//	@Success		200		{object}	types.Response{data=models.CategoryRule}	"Successfully created category rule"
+//	@Failure		400		{object}	validator.GlobalErrorResponse				"Bad request"
+//	@Failure		500		{object}	validator.GlobalErrorResponse				"Internal server error"
+//	@Router			/category-rules [post]
+func (c *CategoryController) CategoryRuleCreate(ctx *fiber.Ctx) error {
+	payload := new(models.CategoryRule)
+	err := c.ParseBodyAndValidate(ctx, payload)
+	if err != nil {
+		return err
+	}
+
+	rule, err := c.categoryService.CreateRule(payload)
+	if err != nil {
+		return c.categoryError("failed to create category rule", err)
+	}
+
+	return ctx.JSON(types.Response{
+		Status:  "success",
+		Message: "Category rule created successfully",
+		Data:    rule,
+	})
+}
+
+// CategoryRuleUpdate godoc
+//
+//	@Summary		Update a category rule
+//	@Description	Update a categorization rule by its ID
+//	@Tags			Category Rules
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int											true	"Category rule ID"
+//	@Param			rule	body		models.CategoryRule							true	"Updated category rule"
+//	@Success		200		{object}	types.Response{data=models.CategoryRule}	"Successfully updated category rule"
+//	@Failure		400		{object}	validator.GlobalErrorResponse				"Bad request"
+//	@Failure		404		{object}	validator.GlobalErrorResponse				"Category rule not found"
+//	@Failure		500		{object}	validator.GlobalErrorResponse				"Internal server error"
+//	@Router			/category-rules/{id} [put]
+func (c *CategoryController) CategoryRuleUpdate(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return &fiber.Error{
+			Code:    fiber.StatusBadRequest,
+			Message: "invalid id",
+		}
+	}
+
+	payload := new(models.CategoryRule)
+	err = c.ParseBodyAndValidate(ctx, payload)
+	if err != nil {
+		return err
+	}
+
+	rule, err := c.categoryService.UpdateRule(uint(id), payload)
+	if err != nil {
+		return c.categoryError("failed to update category rule", err)
+	}
+
+	return ctx.JSON(types.Response{
+		Status:  "success",
+		Message: "Category rule updated successfully",
+		Data:    rule,
+	})
+}
+
+// CategoryRuleDelete godoc
+//
+//	@Summary		Delete a category rule
+//	@Description	Delete a categorization rule by its ID
+//	@Tags			Category Rules
+//	@Produce		json
+//	@Param			id	path		int								true	"Category rule ID"
+//	@Success		200	{object}	types.Response{}				"Successfully deleted category rule"
+//	@Failure		400	{object}	validator.GlobalErrorResponse	"Bad request"
+//	@Failure		500	{object}	validator.GlobalErrorResponse	"Internal server error"
+//	@Router			/category-rules/{id} [delete]
+func (c *CategoryController) CategoryRuleDelete(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return &fiber.Error{
+			Code:    fiber.StatusBadRequest,
+			Message: "invalid id",
+		}
+	}
+
+	err = c.categoryService.DeleteRule(uint(id))
+	if err != nil {
+		return c.categoryError("failed to delete category rule", err)
+	}
+
+	return ctx.JSON(types.Response{
+		Status:  "success",
+		Message: "Category rule deleted successfully",
+	})
+}
+
+// CategoryRulePreview godoc
+//
+//	@Summary		Preview a category rule
+//	@Description	List the stored transactions a rule would match on its own, without saving it
+//	@Tags			Category Rules
+//	@Accept			json
+//	@Produce		json
+//	@Param			rule	body		category.PreviewPayload						true	"Category rule, e_statement_id limits the search to one statement"
+//	@Success		200		{object}	types.Response{data=category.PreviewResult}	"Successfully previewed category rule"
+//	@Failure		400		{object}	validator.GlobalErrorResponse				"Bad request"
+//	@Failure		500		{object}	validator.GlobalErrorResponse				"Internal server error"
+//	@Router			/category-rules/preview [post]
+func (c *CategoryController) CategoryRulePreview(ctx *fiber.Ctx) error {
+	payload := new(category.PreviewPayload)
+	err := c.ParseBodyAndValidate(ctx, payload)
+	if err != nil {
+		return err
+	}
+
+	if payload.Limit <= 0 {
+		payload.Limit = 100
+	}
+
+	result, err := c.categoryService.Preview(&payload.CategoryRule, payload.EStatementID, payload.Limit)
+	if err != nil {
+		return c.categoryError("failed to preview category rule", err)
+	}
+
+	return ctx.JSON(types.Response{
+		Status:  "success",
+		Message: "Category rule previewed successfully",
+		Data:    result,
+	})
+}
+
+// CategoryRuleApply godoc
+//
+//	@Summary		Re-run categorization
+//	@Description	Apply the current rules to the stored transactions of one statement, or of every statement when e_statement_id is empty
+//	@Tags			Category Rules
+//	@Produce		json
+//	@Param			e_statement_id	query		int								false	"E-Statement ID"
+//	@Success		200				{object}	types.Response{data=object}		"Successfully re-ran categorization"
+//	@Failure		400				{object}	validator.GlobalErrorResponse	"Bad request"
+//	@Failure		500				{object}	validator.GlobalErrorResponse	"Internal server error"
+//	@Router			/category-rules/apply [post]
+func (c *CategoryController) CategoryRuleApply(ctx *fiber.Ctx) error {
+	var eStatementID *uint
+	if ctx.Query("e_statement_id") != "" {
+		id := ctx.QueryInt("e_statement_id", 0)
+		if id <= 0 {
+			return &fiber.Error{
+				Code:    fiber.StatusBadRequest,
+				Message: "invalid e_statement_id",
+			}
+		}
+
+		value := uint(id)
+		eStatementID = &value
+	}
+
+	updated, err := c.categoryService.Recategorize(eStatementID)
+	if err != nil {
+		return c.categoryError("failed to re-run categorization", err)
+	}
+
+	return ctx.JSON(types.Response{
+		Status:  "success",
+		Message: "Categorization applied successfully",
+		Data: map[string]interface{}{
+			"updated": updated,
+		},
+	})
+}
+
+func (c *CategoryController) categoryError(message string, err error) error {
+	if err.Error() == "record not found" {
+		return &fiber.Error{
+			Code:    fiber.StatusNotFound,
+			Message: "not found",
+		}
+	}
+
+	if errors.Is(err, category.ErrInvalid) {
+		return &fiber.Error{
+			Code:    fiber.StatusBadRequest,
+			Message: fmt.Sprintf("%s: %s", message, err),
+		}
+	}
+
+	c.log.Error(message, "err", err)
+	return &fiber.Error{
+		Code:    fiber.StatusInternalServerError,
+		Message: fmt.Sprintf("%s: %s", message, err),
+	}
+}
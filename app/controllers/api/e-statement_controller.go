@@ -3,6 +3,8 @@ package api
 import (
 	"fmt"
 	"math"
+	"strconv"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	gonanoid "github.com/matoous/go-nanoid"
@@ -49,14 +51,45 @@ func (*EStatementController) Construct() interface{} {
 //	@Produce		json
 //	@Param			page		query		int																false	"Page number"
 //	@Param			per_page	query		int																false	"Number of items per page"
+//	@Param			holder_name	query		string															false	"Account holder name, partial match"
+//	@Param			rekening	query		string															false	"Account number"
+//	@Param			bank		query		string															false	"Bank name"
+//	@Param			period_from	query		string															false	"Statements covering this date or later (YYYY-MM-DD)"
+//	@Param			period_to	query		string															false	"Statements covering this date or earlier (YYYY-MM-DD)"
+//	@Param			max_confidence	query		number															false	"Statements scanned with this confidence or lower, between 0 and 1"
 //	@Success		200			{object}	types.Response{data=[]models.EStatement,meta=types.PaginationMeta}	"Successfully retrieved e-statements"
+//	@Failure		400			{object}	validator.GlobalErrorResponse									"Bad request"
 //	@Failure		500			{object}	validator.GlobalErrorResponse									"Internal server error"
 //	@Router			/e-statement [get]
 func (c *EStatementController) EStatementFindAll(ctx *fiber.Ctx) error {
 	page := ctx.QueryInt("page", 1)
 	perPage := ctx.QueryInt("per_page", 10)
 
-	estatements, err := c.eStatementService.FindAll(page, perPage)
+	periodFrom, err := queryDate(ctx, "period_from")
+	if err != nil {
+		return err
+	}
+
+	periodTo, err := queryDate(ctx, "period_to")
+	if err != nil {
+		return err
+	}
+
+	maxConfidence, err := queryFloat(ctx, "max_confidence")
+	if err != nil {
+		return err
+	}
+
+	filter := &repositories.EStatementFilter{
+		HolderName:    ctx.Query("holder_name"),
+		Rekening:      ctx.Query("rekening"),
+		Bank:          ctx.Query("bank"),
+		PeriodFrom:    periodFrom,
+		PeriodTo:      periodTo,
+		MaxConfidence: maxConfidence,
+	}
+
+	estatements, err := c.eStatementService.FindAll(page, perPage, filter)
 	if err != nil {
 		c.log.Error("failed get e-statements", "err", err)
 		return &fiber.Error{
@@ -87,8 +120,8 @@ func (c *EStatementController) EStatementFindAll(ctx *fiber.Ctx) error {
 //	@Accept			multipart/form-data
 //	@Produce		json
 //	@Param			file		formData	file		true	"PDF file to scan"
-//	@Param			pdf_library	formData	string		true	"PDF library to use"
-//	@Param			bank		formData	string		false	"Bank name"
+//	@Param			pdf_library	formData	string		false	"PDF library to use, auto when empty"
+//	@Param			bank		formData	string		false	"Bank name, detected from the document when empty"
 //	@Param			time_bomb	formData	string		false	"Time bomb"
 //	@Param			summary		formData	string		false	"Summary"
 //	@Param			scan_only	formData	bool		false	"Only scan the e-statement"
@@ -175,6 +208,40 @@ func (c *EStatementController) EStatementGetSumary(ctx *fiber.Ctx) error {
 	})
 }
 
+// EStatementBanks godoc
+//
+//	@Summary		Get supported banks
+//	@Description	List the banks that can be scanned along with their products
+//	@Tags			E-Statements
+//	@Produce		json
+//	@Success		200	{object}	types.Response{data=[]object}	"Successfully retrieved supported banks"
+//	@Router			/e-statement/banks [get]
+func (c *EStatementController) EStatementBanks(ctx *fiber.Ctx) error {
+	return ctx.JSON(types.Response{
+		Status:  "success",
+		Title:   "Success",
+		Message: "success get supported banks",
+		Data:    c.eStatementService.SupportedBanks(),
+	})
+}
+
+// EStatementLibraries godoc
+//
+//	@Summary		Get supported PDF libraries
+//	@Description	List the PDF libraries that can be used to scan an e-statement
+//	@Tags			E-Statements
+//	@Produce		json
+//	@Success		200	{object}	types.Response{data=[]string}	"Successfully retrieved supported PDF libraries"
+//	@Router			/e-statement/libraries [get]
+func (c *EStatementController) EStatementLibraries(ctx *fiber.Ctx) error {
+	return ctx.JSON(types.Response{
+		Status:  "success",
+		Title:   "Success",
+		Message: "success get supported pdf libraries",
+		Data:    c.eStatementService.SupportedLibraries(),
+	})
+}
+
 func randomStr(length int) string {
 	str, _ := gonanoid.Generate(
 		"abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890",
@@ -182,3 +249,35 @@ func randomStr(length int) string {
 	)
 	return str
 }
+
+func queryDate(ctx *fiber.Ctx, key string) (*time.Time, error) {
+	value := ctx.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, &fiber.Error{
+			Code:    400,
+			Message: fmt.Sprintf("invalid %s, expected YYYY-MM-DD", key),
+		}
+	}
+	return &date, nil
+}
+
+func queryFloat(ctx *fiber.Ctx, key string) (*float64, error) {
+	value := ctx.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, &fiber.Error{
+			Code:    400,
+			Message: fmt.Sprintf("invalid %s, expected a number", key),
+		}
+	}
+	return &number, nil
+}
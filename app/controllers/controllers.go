@@ -11,6 +11,7 @@ import (
 func New() fx.Option {
 	return constructor.Load(
 		&api.EStatementController{},
+		&api.CategoryController{},
 
 		&scan.EStatementController{},
 
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/mrrizkin/omniscan/pkg/categorizer"
+	"github.com/mrrizkin/omniscan/pkg/money"
+	"gorm.io/gorm"
+)
+
+// CategoryRule is a categorization rule stored in the database, rules are
+// applied by ascending Priority and replace the configured rules when any
+// exists.
+type CategoryRule struct {
+	ID          uint           `json:"id"                    gorm:"primary_key"`
+	CreatedAt   *time.Time     `json:"created_at"`
+	UpdatedAt   *time.Time     `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"deleted_at"            gorm:"index"`
+	Priority    int            `json:"priority"              gorm:"index"`
+	Category    string         `json:"category"              gorm:"not null;index"     validate:"required"`
+	Bank        string         `json:"bank,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Direction   string         `json:"direction,omitempty"                             validate:"omitempty,oneof=credit debit"`
+	MinAmount   *money.Money   `json:"min_amount,omitempty"  gorm:"type:numeric(20,2)"`
+	MaxAmount   *money.Money   `json:"max_amount,omitempty"  gorm:"type:numeric(20,2)"`
+}
+
+func (r *CategoryRule) ToRule() categorizer.Rule {
+	return categorizer.Rule{
+		Category:    r.Category,
+		Bank:        r.Bank,
+		Description: r.Description,
+		Direction:   r.Direction,
+		MinAmount:   r.MinAmount,
+		MaxAmount:   r.MaxAmount,
+	}
+}
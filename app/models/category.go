@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Category is a user defined transaction category, Slug is the value
+// stored on the transactions and referenced by the rules
+type Category struct {
+	ID          uint           `json:"id"          gorm:"primary_key"`
+	CreatedAt   *time.Time     `json:"created_at"`
+	UpdatedAt   *time.Time     `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"deleted_at"  gorm:"index"`
+	Slug        string         `json:"slug"        gorm:"unique;not null;index" validate:"required"`
+	Name        string         `json:"name"                                     validate:"required"`
+	Description string         `json:"description"`
+}
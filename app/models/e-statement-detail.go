@@ -3,21 +3,33 @@ package models
 import (
 	"time"
 
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+	"github.com/mrrizkin/omniscan/pkg/money"
 	"gorm.io/gorm"
 )
 
 type EStatementDetail struct {
-	ID              uint           `json:"id"                         gorm:"primary_key"`
-	CreatedAt       *time.Time     `json:"created_at"`
-	UpdatedAt       *time.Time     `json:"updated_at"`
-	DeletedAt       gorm.DeletedAt `json:"deleted_at"                 gorm:"index"`
-	Date            time.Time      `json:"date,omitempty"             gorm:"index"`
-	EStatementID    uint           `json:"e_statement_id,omitempty"   gorm:"index"`
-	Description1    string         `json:"description1,omitempty"`
-	Description2    string         `json:"description2,omitempty"`
-	Branch          string         `json:"branch,omitempty"`
-	Change          float64        `json:"change,omitempty"           gorm:"type:numeric"`
-	TransactionType string         `json:"transaction_type,omitempty" gorm:"index"`
-	Balance         float64        `json:"balance,omitempty"          gorm:"type:numeric"`
-	EStatement      *EStatement    `json:"e_statement,omitempty"     gorm:"foreignKey:EStatementID;references:ID"`
+	ID                  uint           `json:"id"                             gorm:"primary_key"`
+	CreatedAt           *time.Time     `json:"created_at"`
+	UpdatedAt           *time.Time     `json:"updated_at"`
+	DeletedAt           gorm.DeletedAt `json:"deleted_at"                     gorm:"index"`
+	Date                time.Time      `json:"date,omitempty"                 gorm:"index"`
+	EStatementID        uint           `json:"e_statement_id,omitempty"       gorm:"index"`
+	Description1        string         `json:"description1,omitempty"`
+	Description2        string         `json:"description2,omitempty"`
+	Branch              string         `json:"branch,omitempty"`
+	Change              money.Money    `json:"change"                         gorm:"type:numeric(20,2)"`
+	TransactionType     string         `json:"transaction_type,omitempty"     gorm:"index"`
+	Balance             money.Money    `json:"balance"                        gorm:"type:numeric(20,2)"`
+	Currency            string         `json:"currency,omitempty"             gorm:"index;default:IDR"`
+	Channel             string         `json:"channel,omitempty"              gorm:"index"`
+	CounterpartyName    string         `json:"counterparty_name,omitempty"    gorm:"index"`
+	CounterpartyAccount string         `json:"counterparty_account,omitempty" gorm:"index"`
+	CounterpartyBank    string         `json:"counterparty_bank,omitempty"`
+	Reference           string         `json:"reference,omitempty"`
+	Remark              string         `json:"remark,omitempty"`
+	Category            string         `json:"category,omitempty"             gorm:"index"`
+	Sources             types.Sources  `json:"sources,omitempty"              gorm:"type:text"`
+	Confidence          float64        `json:"confidence"                     gorm:"index"`
+	EStatement          *EStatement    `json:"e_statement,omitempty"          gorm:"foreignKey:EStatementID;references:ID"`
 }
@@ -3,6 +3,7 @@ package models
 import (
 	"time"
 
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
 	"gorm.io/gorm"
 )
 
@@ -16,7 +17,15 @@ type EStatement struct {
 	Produk             string              `json:"produk"`
 	Rekening           string              `json:"rekening"`
 	Periode            string              `json:"periode"`
+	PeriodStart        *time.Time          `json:"period_start"  gorm:"index"`
+	PeriodEnd          *time.Time          `json:"period_end"    gorm:"index"`
+	HolderName         string              `json:"holder_name"   gorm:"index"`
+	Branch             string              `json:"branch"`
+	Currency           string              `json:"currency"`
+	AccountType        string              `json:"account_type"`
 	Expired            *time.Time          `json:"expired"       gorm:"index"`
+	Confidence         float64             `json:"confidence"    gorm:"index"`
+	Warnings           types.Warnings      `json:"warnings"      gorm:"type:text"`
 	EStatementDetail   []EStatementDetail  `json:"e_statement_detail" gorm:"foreignKey:EStatementID;references:ID"`
 	EStatementMetadata *EStatementMetadata `json:"e_statement_metadata" gorm:"foreignKey:EStatementID;references:ID"`
 }
@@ -31,6 +31,8 @@ func (m *Model) Construct() interface{} {
 				&EStatement{},
 				&EStatementDetail{},
 				&EStatementMetadata{},
+				&Category{},
+				&CategoryRule{},
 				&Permission{},
 				&Role{},
 				&RolePermission{},
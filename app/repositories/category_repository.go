@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"github.com/mrrizkin/omniscan/app/models"
+	"github.com/mrrizkin/omniscan/app/providers/database"
+)
+
+type CategoryRepository struct {
+	db *database.Database
+}
+
+func (r *CategoryRepository) Construct() interface{} {
+	return func(db *database.Database) *CategoryRepository {
+		return &CategoryRepository{db}
+	}
+}
+
+// FindAllRules returns the rules in the order they are applied
+func (r *CategoryRepository) FindAllRules() ([]models.CategoryRule, error) {
+	rules := make([]models.CategoryRule, 0)
+	err := r.db.Order("priority ASC").Order("id ASC").Find(&rules).Error
+	return rules, err
+}
+
+func (r *CategoryRepository) FindRuleByID(id uint) (*models.CategoryRule, error) {
+	rule := new(models.CategoryRule)
+	err := r.db.First(rule, id).Error
+	return rule, err
+}
+
+func (r *CategoryRepository) CreateRule(rule *models.CategoryRule) error {
+	return r.db.Create(rule).Error
+}
+
+func (r *CategoryRepository) UpdateRule(rule *models.CategoryRule) error {
+	return r.db.Save(rule).Error
+}
+
+func (r *CategoryRepository) DeleteRule(id uint) error {
+	return r.db.Delete(&models.CategoryRule{}, id).Error
+}
+
+func (r *CategoryRepository) CountRulesByCategory(category string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.CategoryRule{}).Where("category = ?", category).Count(&count).Error
+	return count, err
+}
+
+func (r *CategoryRepository) FindAll() ([]models.Category, error) {
+	categories := make([]models.Category, 0)
+	err := r.db.Order("slug ASC").Find(&categories).Error
+	return categories, err
+}
+
+func (r *CategoryRepository) FindByID(id uint) (*models.Category, error) {
+	category := new(models.Category)
+	err := r.db.First(category, id).Error
+	return category, err
+}
+
+func (r *CategoryRepository) FindBySlug(slug string) (*models.Category, error) {
+	category := new(models.Category)
+	err := r.db.Where("slug = ?", slug).First(category).Error
+	return category, err
+}
+
+// SlugExists tells whether a category ever used slug, deleted categories
+// included
+func (r *CategoryRepository) SlugExists(slug string) (bool, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&models.Category{}).Where("slug = ?", slug).Count(&count).Error
+	return count != 0, err
+}
+
+func (r *CategoryRepository) Create(category *models.Category) error {
+	return r.db.Create(category).Error
+}
+
+func (r *CategoryRepository) Update(category *models.Category) error {
+	return r.db.Save(category).Error
+}
+
+func (r *CategoryRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Category{}, id).Error
+}
@@ -3,41 +3,122 @@ package repositories
 import (
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/mrrizkin/omniscan/app/models"
 	"github.com/mrrizkin/omniscan/app/providers/database"
 	"github.com/mrrizkin/omniscan/app/providers/logger"
+	"github.com/mrrizkin/omniscan/pkg/money"
 	"gorm.io/gorm"
 )
 
 type EStatementRepository struct {
-	db  *database.Database
-	log *logger.Logger
+	db    *database.Database
+	log   *logger.Logger
+	rates *money.Rates
 }
 
 func (r *EStatementRepository) Construct() interface{} {
 	return func(db *database.Database, log *logger.Logger) *EStatementRepository {
-		return &EStatementRepository{db, log}
+		return &EStatementRepository{db: db, log: log}
 	}
 }
 
+// WithRates returns a repository whose aggregates convert every amount
+// into the base currency of rates
+func (r *EStatementRepository) WithRates(rates *money.Rates) *EStatementRepository {
+	return &EStatementRepository{db: r.db, log: r.log, rates: rates}
+}
+
+// amount returns the expression reading an amount column, converted into
+// the base currency when the repository has rates. Currencies are checked
+// to be 3 upper case letters when the rates are loaded.
+func (r *EStatementRepository) amount(column string) string {
+	if r.rates == nil {
+		return column
+	}
+
+	var sb strings.Builder
+	sb.WriteString("(" + column + " * CASE currency")
+	for _, currency := range r.rates.Currencies() {
+		rate, _ := r.rates.Rate(currency)
+		fmt.Fprintf(&sb, " WHEN '%s' THEN %s", currency, strconv.FormatFloat(rate, 'f', -1, 64))
+	}
+	sb.WriteString(" ELSE 1 END)")
+	return sb.String()
+}
+
 func (r *EStatementRepository) Begin() *gorm.DB {
 	return r.db.Begin()
 }
 
-func (r *EStatementRepository) FindAll(page, perPage int) ([]models.EStatement, error) {
+// EStatementFilter narrows the e-statement list, empty fields are ignored
+type EStatementFilter struct {
+	HolderName string
+	Rekening   string
+	Bank       string
+	PeriodFrom *time.Time
+	PeriodTo   *time.Time
+
+	// MaxConfidence keeps the statements that need a manual review
+	MaxConfidence *float64
+}
+
+func (f *EStatementFilter) where() (string, []interface{}) {
+	if f == nil {
+		return "", nil
+	}
+
+	wb := whereBuilder()
+	if f.HolderName != "" {
+		wb.And("LOWER(holder_name) LIKE ?", "%"+strings.ToLower(f.HolderName)+"%")
+	}
+	if f.Rekening != "" {
+		wb.And("rekening = ?", f.Rekening)
+	}
+	if f.Bank != "" {
+		wb.And("bank = ?", f.Bank)
+	}
+	// statements overlapping the requested period
+	if f.PeriodFrom != nil {
+		wb.And("period_end >= ?", *f.PeriodFrom)
+	}
+	if f.PeriodTo != nil {
+		wb.And("period_start <= ?", *f.PeriodTo)
+	}
+	if f.MaxConfidence != nil {
+		wb.And("confidence <= ?", *f.MaxConfidence)
+	}
+	return wb.Get()
+}
+
+func (r *EStatementRepository) FindAll(
+	page, perPage int,
+	filter *EStatementFilter,
+) ([]models.EStatement, error) {
 	eStatement := make([]models.EStatement, 0)
-	err := r.db.
+	gormDB := r.db.Model(&models.EStatement{})
+	if where, whereArgs := filter.where(); where != "" {
+		gormDB = gormDB.Where(where, whereArgs...)
+	}
+
+	err := gormDB.
 		Offset((page - 1) * perPage).
 		Limit(perPage).
 		Find(&eStatement).Error
 	return eStatement, err
 }
 
-func (r *EStatementRepository) FindAllCount() (int64, error) {
+func (r *EStatementRepository) FindAllCount(filter *EStatementFilter) (int64, error) {
 	var count int64 = 0
-	err := r.db.Model(&models.EStatement{}).Count(&count).Error
+	gormDB := r.db.Model(&models.EStatement{})
+	if where, whereArgs := filter.where(); where != "" {
+		gormDB = gormDB.Where(where, whereArgs...)
+	}
+
+	err := gormDB.Count(&count).Error
 	return count, err
 }
 
@@ -53,9 +134,15 @@ func (r *EStatementRepository) AggregateDetail(eStatementDetail []models.EStatem
 	return db.Create(eStatementDetail).Error
 }
 
+// detailsInScanOrder preloads the details in the order they were scanned,
+// the stored warnings point at them by their index
+func detailsInScanOrder(db *gorm.DB) *gorm.DB {
+	return db.Order("id ASC")
+}
+
 func (r *EStatementRepository) IsFileAlreadyScanned(filename string) bool {
 	eStatement := new(models.EStatement)
-	err := r.db.Preload("EStatementDetail").Where("filename = ?", filename).
+	err := r.db.Preload("EStatementDetail", detailsInScanOrder).Where("filename = ?", filename).
 		First(eStatement).
 		Error
 	return !errors.Is(err, gorm.ErrRecordNotFound)
@@ -64,7 +151,7 @@ func (r *EStatementRepository) IsFileAlreadyScanned(filename string) bool {
 func (r *EStatementRepository) GetEStatementByFilename(filename string) (*models.EStatement, error) {
 	eStatement := new(models.EStatement)
 	err := r.db.Where("filename = ?", filename).
-		Preload("EStatementDetail").
+		Preload("EStatementDetail", detailsInScanOrder).
 		Preload("EStatementMetadata").
 		First(eStatement).
 		Error
@@ -100,10 +187,49 @@ func (r *EStatementRepository) GetDetail(eStatementID uint) ([]models.EStatement
 	return eStatement, nil
 }
 
-func (r *EStatementRepository) GetBalance(idEstatement uint, balanceType string) (float64, error) {
+// GetBanks returns the id and bank of a statement, or of every statement
+// when eStatementID is nil
+func (r *EStatementRepository) GetBanks(eStatementID *uint) ([]models.EStatement, error) {
+	eStatements := make([]models.EStatement, 0)
+	gormDB := r.db.Select("id", "bank")
+	if eStatementID != nil {
+		gormDB = gormDB.Where("id = ?", *eStatementID)
+	}
+
+	err := gormDB.Order("id ASC").Find(&eStatements).Error
+	return eStatements, err
+}
+
+// GetDetailInBatches calls fn with the details of a statement, batchSize
+// rows at a time
+func (r *EStatementRepository) GetDetailInBatches(
+	eStatementID uint,
+	batchSize int,
+	fn func(details []models.EStatementDetail) error,
+) error {
+	details := make([]models.EStatementDetail, 0)
+	return r.db.Where("e_statement_id = ?", eStatementID).
+		FindInBatches(&details, batchSize, func(_ *gorm.DB, _ int) error {
+			return fn(details)
+		}).Error
+}
+
+func (r *EStatementRepository) UpdateDetailCategory(ids []uint, category string) error {
+	return r.db.Model(&models.EStatementDetail{}).
+		Where("id IN ?", ids).
+		Update("category", category).Error
+}
+
+func (r *EStatementRepository) CountDetailsByCategory(category string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.EStatementDetail{}).Where("category = ?", category).Count(&count).Error
+	return count, err
+}
+
+func (r *EStatementRepository) GetBalance(idEstatement uint, balanceType string) (money.Money, error) {
 	var err error
 	var result struct {
-		Balance float64
+		Balance money.Money
 	}
 
 	gormDB := r.db.Model(&models.EStatementDetail{}).
@@ -111,17 +237,17 @@ func (r *EStatementRepository) GetBalance(idEstatement uint, balanceType string)
 
 	switch balanceType {
 	case "start":
-		err = gormDB.Select("balance").
+		err = gormDB.Select(r.amount("balance") + " as balance").
 			Order("date ASC").
 			Limit(1).
 			Scan(&result).Error
 	case "end":
-		err = gormDB.Select("balance").
+		err = gormDB.Select(r.amount("balance") + " as balance").
 			Order("date DESC").
 			Limit(1).
 			Scan(&result).Error
 	case "avg":
-		err = gormDB.Select("AVG(balance) as balance").
+		err = gormDB.Select("AVG(" + r.amount("balance") + ") as balance").
 			Scan(&result).Error
 	default:
 		return 0, fmt.Errorf("balanceType: %s is not supportetd", balanceType)
@@ -133,23 +259,20 @@ func (r *EStatementRepository) GetBalance(idEstatement uint, balanceType string)
 func (r *EStatementRepository) GetTransactionStatsByTransactionType(
 	eStatementID uint,
 	transactionType, statType string,
-) (float64, error) {
+) (money.Money, error) {
 	var err error
 	var result struct {
-		Total float64
+		Total money.Money
 	}
 	gormDB := r.db.Model(&models.EStatementDetail{}).
 		Where("e_statement_id = ? AND transaction_type = ?", eStatementID, transactionType)
 
 	switch statType {
 	case "total":
-		err = gormDB.Select("SUM(change) as total").
+		err = gormDB.Select("SUM(" + r.amount("change") + ") as total").
 			Scan(&result).Error
 	case "avg":
-		err = gormDB.Select("AVG(change) as total").
-			Scan(&result).Error
-	case "count":
-		err = gormDB.Select("COUNT(*) as total").
+		err = gormDB.Select("AVG(" + r.amount("change") + ") as total").
 			Scan(&result).Error
 	default:
 		return 0, fmt.Errorf("statType: %s is not supportetd", statType)
@@ -158,6 +281,17 @@ func (r *EStatementRepository) GetTransactionStatsByTransactionType(
 	return result.Total, err
 }
 
+func (r *EStatementRepository) GetTransactionCountByTransactionType(
+	eStatementID uint,
+	transactionType string,
+) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.EStatementDetail{}).
+		Where("e_statement_id = ? AND transaction_type = ?", eStatementID, transactionType).
+		Count(&count).Error
+	return count, err
+}
+
 func (r *EStatementRepository) GetTopChangeByTransactionType(
 	eStatementID uint,
 	transactionType string,
@@ -165,7 +299,7 @@ func (r *EStatementRepository) GetTopChangeByTransactionType(
 ) ([]models.EStatementDetail, error) {
 	var eStatementDetail []models.EStatementDetail
 	err := r.db.Where("e_statement_id = ? AND transaction_type = ?", eStatementID, transactionType).
-		Order("change DESC").
+		Order(r.amount("change") + " DESC").
 		Limit(limit).
 		Find(&eStatementDetail).Error
 	return eStatementDetail, err
@@ -183,45 +317,59 @@ func (r *EStatementRepository) GetAnomalyTransactions(
 
 func (r *EStatementRepository) GetTotalChangeByCategory(
 	eStatementID uint,
-	categoryType string,
-) (float64, error) {
-	var err error
+	category string,
+) (money.Money, error) {
 	var result struct {
-		Total float64
-	}
-
-	wb := whereBuilder()
-	wb.And("e_statement_id = ?", eStatementID)
-	switch categoryType {
-	case "bank_fee":
-		wb.And("((description1 = 'SWITCHING DB' AND balance != 0) OR (description1 = 'BI-FAST DB' AND description2 ILIKE '%BIAYA%') OR description1 = 'BIAYA ADM')")
-	case "interest":
-		wb.And("description1 = 'BUNGA'")
-	case "tax":
-		wb.And("description1 ILIKE '%PAJAK%'")
-	case "digital_revenue":
-		wb.And("description2 ILIKE '%ESPAY DEBIT%'")
-	case "transfer_in":
-		wb.And("transaction_type = 'credit' AND (description1 LIKE '%TRSF%' OR description1 = 'SWITCHING CR')")
-		wb.And("description2 NOT LIKE '%ESPAY DEBIT%'")
-	case "transfer_out":
-		wb.And("transaction_type = 'debit' AND (description1 LIKE '%TRSF%' OR (description1 = 'SWITCHING DB' AND balance = 0) OR (description1 = 'BI-FAST DB' AND description2 ILIKE '%BIF TRANSFER%') OR description1 ILIKE '%BYR%')")
-	case "cash_withdrawal":
-		wb.And("description1 ILIKE '%TARIKAN ATM%'")
-	default:
-		return 0, fmt.Errorf("categoryType: %s is not supportetd", categoryType)
+		Total money.Money
 	}
 
-	where, whereArgs := wb.Get()
-	err = r.db.Where(where, whereArgs...).
-		Model(models.EStatementDetail{}).
-		Select("SUM(change) as total").Scan(&result).Error
+	err := r.db.Model(&models.EStatementDetail{}).
+		Where("e_statement_id = ? AND category = ?", eStatementID, category).
+		Select("SUM(" + r.amount("change") + ") as total").
+		Scan(&result).Error
 	return result.Total, err
 }
 
+type CategoryAmount struct {
+	Category string      `json:"category"`
+	Amount   money.Money `json:"amount"`
+	Count    int64       `json:"count"`
+}
+
+// GetTotalChangeGroupByCategory adds up the transactions of every category,
+// uncategorized transactions are left out
+func (r *EStatementRepository) GetTotalChangeGroupByCategory(
+	eStatementID uint,
+) ([]CategoryAmount, error) {
+	results := make([]CategoryAmount, 0)
+	err := r.db.Model(&models.EStatementDetail{}).
+		Where("e_statement_id = ? AND category != ''", eStatementID).
+		Select("category, SUM(" + r.amount("change") + ") as amount, COUNT(*) as count").
+		Group("category").
+		Order("category").
+		Scan(&results).Error
+	return results, err
+}
+
+// GetCurrencies lists the distinct currencies of the transactions
+func (r *EStatementRepository) GetCurrencies(eStatementID uint) ([]string, error) {
+	var currencies []string
+	err := r.db.Model(&models.EStatementDetail{}).
+		Where("e_statement_id = ?", eStatementID).
+		Distinct("currency").
+		Order("currency").
+		Pluck("currency", &currencies).Error
+	return currencies, err
+}
+
 type MonthlyAmount struct {
-	Date   time.Time `json:"date"`
-	Amount float64   `json:"amount"`
+	Date   time.Time   `json:"date"`
+	Amount money.Money `json:"amount"`
+}
+
+type MonthlyCount struct {
+	Date  time.Time `json:"date"`
+	Count int64     `json:"count"`
 }
 
 type MonthlyEStatementDetails struct {
@@ -237,7 +385,7 @@ func (r *EStatementRepository) GetMonthlyBalances(eStatementID uint, balanceType
 	case "start":
 		sql = `SELECT DISTINCT ON (DATE_TRUNC('month', date))
     date,
-    balance as amount
+    %s as amount
 FROM
     e_statement_details
 WHERE e_statement_id = ?
@@ -247,7 +395,7 @@ ORDER BY
 	case "end":
 		sql = `SELECT DISTINCT ON (DATE_TRUNC('month', date))
     date,
-    balance as amount
+    %s as amount
 FROM
     e_statement_details
 WHERE e_statement_id = ?
@@ -257,7 +405,7 @@ ORDER BY
 	case "avg":
 		sql = `SELECT
     DATE_TRUNC('month', date) AS date,
-    AVG(balance) AS amount
+    AVG(%s) AS amount
 FROM
     e_statement_details
 WHERE e_statement_id = ?
@@ -269,6 +417,7 @@ ORDER BY
 		return nil, fmt.Errorf("balanceType: %s is not supportetd", balanceType)
 	}
 
+	sql = fmt.Sprintf(sql, r.amount("balance"))
 	err := r.db.Raw(sql, eStatementID).Scan(&results).Error
 	if err != nil {
 		return nil, fmt.Errorf("failed to get monthly balances: %w", err)
@@ -288,7 +437,7 @@ func (r *EStatementRepository) GetMonthlyTransactionStatsByTransactionType(
 	case "total":
 		sql = `SELECT
     DATE_TRUNC('month', date) AS date,
-    SUM(balance) AS amount
+    SUM(%s) AS amount
 FROM
     e_statement_details
 WHERE e_statement_id = ? AND transaction_type = ?
@@ -299,7 +448,7 @@ ORDER BY
 	case "avg":
 		sql = `SELECT
     DATE_TRUNC('month', date) AS date,
-    AVG(balance) AS amount
+    AVG(%s) AS amount
 FROM
     e_statement_details
 WHERE e_statement_id = ? AND transaction_type = ?
@@ -307,22 +456,31 @@ GROUP BY
     DATE_TRUNC('month', date)
 ORDER BY
     date`
-	case "count":
-		sql = `SELECT
+	default:
+		return nil, fmt.Errorf("statType: %s is not supportetd", statType)
+	}
+
+	sql = fmt.Sprintf(sql, r.amount("change"))
+	err := r.db.Raw(sql, eStatementID, transactionType).Scan(&results).Error
+	return results, err
+}
+
+func (r *EStatementRepository) GetMonthlyTransactionCountByTransactionType(
+	eStatementID uint,
+	transactionType string,
+) ([]MonthlyCount, error) {
+	var results []MonthlyCount
+	err := r.db.Raw(`SELECT
     DATE_TRUNC('month', date) AS date,
-    COUNT(*) AS amount
+    COUNT(*) AS count
 FROM
     e_statement_details
 WHERE e_statement_id = ? AND transaction_type = ?
 GROUP BY
     DATE_TRUNC('month', date)
 ORDER BY
-    date`
-	default:
-		return nil, fmt.Errorf("statType: %s is not supportetd", statType)
-	}
-
-	err := r.db.Raw(sql, eStatementID, transactionType).Scan(&results).Error
+    date`, eStatementID, transactionType).
+		Scan(&results).Error
 	return results, err
 }
 
@@ -358,7 +516,7 @@ AND transaction_type = ?
 AND date >= ?
 AND date <= ?
       `, eStatementID, transactionType, start, end).
-			Order("change DESC").
+			Order(r.amount("change") + " DESC").
 			Limit(limit).
 			Find(&detail).Error
 
@@ -10,5 +10,6 @@ func New() fx.Option {
 	return constructor.Load(
 		&UserRepository{},
 		&EStatementRepository{},
+		&CategoryRepository{},
 	)
 }
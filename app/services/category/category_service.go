@@ -0,0 +1,337 @@
+package category
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mrrizkin/omniscan/app/models"
+	"github.com/mrrizkin/omniscan/app/providers/logger"
+	"github.com/mrrizkin/omniscan/app/repositories"
+	"github.com/mrrizkin/omniscan/pkg/categorizer"
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+)
+
+// ErrInvalid is returned when a category or a rule can't be saved as given
+var ErrInvalid = errors.New("invalid payload")
+
+const batchSize = 500
+
+type CategoryService struct {
+	log *logger.Logger
+
+	repo           *repositories.CategoryRepository
+	eStatementRepo *repositories.EStatementRepository
+	rules          categorizer.Rules
+}
+
+func (*CategoryService) Construct() interface{} {
+	return func(
+		log *logger.Logger,
+
+		repo *repositories.CategoryRepository,
+		eStatementRepo *repositories.EStatementRepository,
+		rules categorizer.Rules,
+	) *CategoryService {
+		return &CategoryService{log, repo, eStatementRepo, rules}
+	}
+}
+
+// Categorizer is built from the rules stored in the database followed by
+// the configured rules, a stored rule wins over a configured one matching
+// the same transaction
+func (s *CategoryService) Categorizer() (*categorizer.Categorizer, error) {
+	categoryRules, err := s.repo.FindAllRules()
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make(categorizer.Rules, 0, len(categoryRules)+len(s.rules))
+	for _, rule := range categoryRules {
+		rules = append(rules, rule.ToRule())
+	}
+	rules = append(rules, s.rules...)
+
+	return categorizer.New(rules)
+}
+
+// Seed creates the categories used by the configured rules so they can be
+// listed and picked by the stored rules, a category that was deleted is
+// not created again
+func (s *CategoryService) Seed() error {
+	for _, rule := range s.rules {
+		exists, err := s.repo.SlugExists(rule.Category)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+
+		err = s.repo.Create(&models.Category{
+			Slug: rule.Category,
+			Name: categoryName(rule.Category),
+		})
+		if err != nil {
+			return err
+		}
+		s.log.Info("seeded category", "slug", rule.Category)
+	}
+
+	return nil
+}
+
+func (s *CategoryService) FindAll() ([]models.Category, error) {
+	return s.repo.FindAll()
+}
+
+func (s *CategoryService) FindByID(id uint) (*models.Category, error) {
+	return s.repo.FindByID(id)
+}
+
+// Create refuses a slug used by a deleted category too, the slug is unique
+// across the deleted rows as well
+func (s *CategoryService) Create(category *models.Category) (*models.Category, error) {
+	exists, err := s.repo.SlugExists(category.Slug)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, fmt.Errorf("%w: category %s already exists", ErrInvalid, category.Slug)
+	}
+
+	err = s.repo.Create(category)
+	if err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// Update changes the name and description, the slug is kept as it is
+// stored on the rules and the transactions
+func (s *CategoryService) Update(id uint, category *models.Category) (*models.Category, error) {
+	categoryExist, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	categoryExist.Name = category.Name
+	categoryExist.Description = category.Description
+
+	err = s.repo.Update(categoryExist)
+	if err != nil {
+		return nil, err
+	}
+
+	return categoryExist, nil
+}
+
+func (s *CategoryService) Delete(id uint) error {
+	category, err := s.repo.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	count, err := s.repo.CountRulesByCategory(category.Slug)
+	if err != nil {
+		return err
+	}
+	if count != 0 {
+		return fmt.Errorf("%w: category %s is used by %d rules", ErrInvalid, category.Slug, count)
+	}
+
+	for _, rule := range s.rules {
+		if rule.Category == category.Slug {
+			return fmt.Errorf("%w: category %s is used by the configured rules", ErrInvalid, category.Slug)
+		}
+	}
+
+	count, err = s.eStatementRepo.CountDetailsByCategory(category.Slug)
+	if err != nil {
+		return err
+	}
+	if count != 0 {
+		return fmt.Errorf("%w: category %s is used by %d transactions", ErrInvalid, category.Slug, count)
+	}
+
+	return s.repo.Delete(id)
+}
+
+func (s *CategoryService) FindAllRules() ([]models.CategoryRule, error) {
+	return s.repo.FindAllRules()
+}
+
+func (s *CategoryService) FindRuleByID(id uint) (*models.CategoryRule, error) {
+	return s.repo.FindRuleByID(id)
+}
+
+func (s *CategoryService) CreateRule(rule *models.CategoryRule) (*models.CategoryRule, error) {
+	if err := s.validateRule(rule); err != nil {
+		return nil, err
+	}
+
+	err := s.repo.CreateRule(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+func (s *CategoryService) UpdateRule(id uint, rule *models.CategoryRule) (*models.CategoryRule, error) {
+	ruleExist, err := s.repo.FindRuleByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateRule(rule); err != nil {
+		return nil, err
+	}
+
+	ruleExist.Priority = rule.Priority
+	ruleExist.Category = rule.Category
+	ruleExist.Bank = rule.Bank
+	ruleExist.Description = rule.Description
+	ruleExist.Direction = rule.Direction
+	ruleExist.MinAmount = rule.MinAmount
+	ruleExist.MaxAmount = rule.MaxAmount
+
+	err = s.repo.UpdateRule(ruleExist)
+	if err != nil {
+		return nil, err
+	}
+
+	return ruleExist, nil
+}
+
+func (s *CategoryService) DeleteRule(id uint) error {
+	return s.repo.DeleteRule(id)
+}
+
+// Preview lists the stored transactions matched by rule on its own, without
+// the rules before it, up to limit of them along with the total count.
+// Every statement is searched when eStatementID is nil.
+func (s *CategoryService) Preview(
+	rule *models.CategoryRule,
+	eStatementID *uint,
+	limit int,
+) (*PreviewResult, error) {
+	c, err := categorizer.New(categorizer.Rules{rule.ToRule()})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalid, err)
+	}
+
+	result := &PreviewResult{
+		Transactions: make([]models.EStatementDetail, 0),
+	}
+	err = s.eachDetailBatch(eStatementID, func(bank string, details []models.EStatementDetail) error {
+		for _, detail := range details {
+			if c.Categorize(bank, detailTransaction(detail)) == "" {
+				continue
+			}
+
+			result.Total++
+			if len(result.Transactions) < limit {
+				result.Transactions = append(result.Transactions, detail)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Recategorize applies the current rules to the stored transactions of a
+// statement, or of every statement when eStatementID is nil, and returns
+// how many transactions changed category
+func (s *CategoryService) Recategorize(eStatementID *uint) (int, error) {
+	c, err := s.Categorizer()
+	if err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	err = s.eachDetailBatch(eStatementID, func(bank string, details []models.EStatementDetail) error {
+		changes := make(map[string][]uint)
+		for _, detail := range details {
+			category := c.Categorize(bank, detailTransaction(detail))
+			if category != detail.Category {
+				changes[category] = append(changes[category], detail.ID)
+			}
+		}
+
+		for category, ids := range changes {
+			if err := s.eStatementRepo.UpdateDetailCategory(ids, category); err != nil {
+				return err
+			}
+			updated += len(ids)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	s.log.Info("recategorized transactions", "updated", updated)
+	return updated, nil
+}
+
+func (s *CategoryService) validateRule(rule *models.CategoryRule) error {
+	if err := rule.ToRule().Validate(); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalid, err)
+	}
+
+	if _, err := s.repo.FindBySlug(rule.Category); err != nil {
+		return fmt.Errorf("%w: unknown category %s", ErrInvalid, rule.Category)
+	}
+
+	return nil
+}
+
+func (s *CategoryService) eachDetailBatch(
+	eStatementID *uint,
+	fn func(bank string, details []models.EStatementDetail) error,
+) error {
+	eStatements, err := s.eStatementRepo.GetBanks(eStatementID)
+	if err != nil {
+		return err
+	}
+
+	for _, eStatement := range eStatements {
+		err := s.eStatementRepo.GetDetailInBatches(
+			eStatement.ID,
+			batchSize,
+			func(details []models.EStatementDetail) error {
+				return fn(eStatement.Bank, details)
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// categoryName turns a slug into a readable name, e.g. "bank_fee" gives
+// "Bank Fee"
+func categoryName(slug string) string {
+	words := strings.Fields(strings.ReplaceAll(slug, "_", " "))
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+func detailTransaction(detail models.EStatementDetail) *types.Transaction {
+	return &types.Transaction{
+		Description1:    detail.Description1,
+		Description2:    detail.Description2,
+		Change:          detail.Change,
+		TransactionType: detail.TransactionType,
+	}
+}
@@ -0,0 +1,15 @@
+package category
+
+import "github.com/mrrizkin/omniscan/app/models"
+
+type PreviewResult struct {
+	Total        int                       `json:"total"`
+	Transactions []models.EStatementDetail `json:"transactions"`
+}
+
+type PreviewPayload struct {
+	models.CategoryRule
+
+	EStatementID *uint `json:"e_statement_id"`
+	Limit        int   `json:"limit"`
+}
@@ -14,13 +14,23 @@ func (s *EStatementService) convertEStatementDetailToTransactions(
 	transactions := make([]*types.Transaction, len(details))
 	for i, detail := range details {
 		transactions[i] = &types.Transaction{
-			Date:            detail.Date,
-			Description1:    detail.Description1,
-			Description2:    detail.Description2,
-			Branch:          detail.Branch,
-			Change:          detail.Change,
-			TransactionType: detail.TransactionType,
-			Balance:         detail.Balance,
+			Date:                detail.Date,
+			Description1:        detail.Description1,
+			Description2:        detail.Description2,
+			Branch:              detail.Branch,
+			Change:              detail.Change,
+			TransactionType:     detail.TransactionType,
+			Balance:             detail.Balance,
+			Currency:            detail.Currency,
+			Channel:             detail.Channel,
+			CounterpartyName:    detail.CounterpartyName,
+			CounterpartyAccount: detail.CounterpartyAccount,
+			CounterpartyBank:    detail.CounterpartyBank,
+			Reference:           detail.Reference,
+			Remark:              detail.Remark,
+			Category:            detail.Category,
+			Sources:             detail.Sources,
+			Confidence:          detail.Confidence,
 		}
 	}
 	return transactions
@@ -13,12 +13,20 @@ func (s *EStatementService) createEStatementModel(
 	expiry *time.Time,
 ) *models.EStatement {
 	return &models.EStatement{
-		Bank:     scanResult.Info.Bank,
-		Filename: filename,
-		Produk:   scanResult.Info.Produk,
-		Rekening: scanResult.Info.Rekening,
-		Periode:  scanResult.Info.Periode,
-		Expired:  expiry,
+		Bank:        scanResult.Info.Bank,
+		Filename:    filename,
+		Produk:      scanResult.Info.Produk,
+		Rekening:    scanResult.Info.Rekening,
+		Periode:     scanResult.Info.Periode,
+		PeriodStart: scanResult.Info.PeriodStart,
+		PeriodEnd:   scanResult.Info.PeriodEnd,
+		HolderName:  scanResult.Info.HolderName,
+		Branch:      scanResult.Info.Branch,
+		Currency:    scanResult.Info.Currency,
+		AccountType: scanResult.Info.AccountType,
+		Expired:     expiry,
+		Confidence:  scanResult.Confidence,
+		Warnings:    scanResult.Warnings,
 	}
 }
 
@@ -36,14 +44,24 @@ func (s *EStatementService) createEStatementDetailModels(
 	eStatementDetails := make([]models.EStatementDetail, len(transactions))
 	for i, detail := range transactions {
 		eStatementDetails[i] = models.EStatementDetail{
-			Date:            detail.Date,
-			EStatementID:    eStatementID,
-			Description1:    detail.Description1,
-			Description2:    detail.Description2,
-			Branch:          detail.Branch,
-			Change:          detail.Change,
-			TransactionType: detail.TransactionType,
-			Balance:         detail.Balance,
+			Date:                detail.Date,
+			EStatementID:        eStatementID,
+			Description1:        detail.Description1,
+			Description2:        detail.Description2,
+			Branch:              detail.Branch,
+			Change:              detail.Change,
+			TransactionType:     detail.TransactionType,
+			Balance:             detail.Balance,
+			Currency:            detail.Currency,
+			Channel:             detail.Channel,
+			CounterpartyName:    detail.CounterpartyName,
+			CounterpartyAccount: detail.CounterpartyAccount,
+			CounterpartyBank:    detail.CounterpartyBank,
+			Reference:           detail.Reference,
+			Remark:              detail.Remark,
+			Category:            detail.Category,
+			Sources:             detail.Sources,
+			Confidence:          detail.Confidence,
 		}
 	}
 	return eStatementDetails
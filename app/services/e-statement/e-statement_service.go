@@ -7,16 +7,20 @@ import (
 
 	"github.com/mrrizkin/omniscan/app/providers/logger"
 	"github.com/mrrizkin/omniscan/app/repositories"
+	"github.com/mrrizkin/omniscan/app/services/category"
 	estatementscanner "github.com/mrrizkin/omniscan/pkg/e-statement-scanner"
 
 	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+	"github.com/mrrizkin/omniscan/pkg/money"
 )
 
 type EStatementService struct {
 	log *logger.Logger
 
-	repo    *repositories.EStatementRepository
-	scanner *estatementscanner.EStatementScanner
+	repo            *repositories.EStatementRepository
+	categoryService *category.CategoryService
+	scanner         *estatementscanner.EStatementScanner
+	rates           *money.Rates
 }
 
 func (*EStatementService) Construct() interface{} {
@@ -24,19 +28,24 @@ func (*EStatementService) Construct() interface{} {
 		log *logger.Logger,
 
 		repo *repositories.EStatementRepository,
+		categoryService *category.CategoryService,
 		scanner *estatementscanner.EStatementScanner,
+		rates *money.Rates,
 	) *EStatementService {
-		return &EStatementService{log, repo, scanner}
+		return &EStatementService{log, repo, categoryService, scanner, rates}
 	}
 }
 
-func (s *EStatementService) FindAll(page, perPage int) (*PaginatedEStatement, error) {
-	eStatements, err := s.repo.FindAll(page, perPage)
+func (s *EStatementService) FindAll(
+	page, perPage int,
+	filter *repositories.EStatementFilter,
+) (*PaginatedEStatement, error) {
+	eStatements, err := s.repo.FindAll(page, perPage, filter)
 	if err != nil {
 		return nil, err
 	}
 
-	eStatementsCount, err := s.repo.FindAllCount()
+	eStatementsCount, err := s.repo.FindAllCount(filter)
 	if err != nil {
 		return nil, err
 	}
@@ -82,6 +91,14 @@ func (s *EStatementService) GetSummary(eStatementID uint) (*OverallSummary, erro
 	return s.getSummary(eStatementID)
 }
 
+func (s *EStatementService) SupportedBanks() []types.BankInfo {
+	return s.scanner.Banks()
+}
+
+func (s *EStatementService) SupportedLibraries() []string {
+	return s.scanner.Libraries()
+}
+
 func (s *EStatementService) getExistingEStatementResponse(
 	fileHeader *multipart.FileHeader,
 	summaryField []string,
@@ -108,13 +125,22 @@ func (s *EStatementService) getExistingEStatementResponse(
 	scanResult := types.ScanResult{
 		Transactions: transactions,
 		Info: types.ScanInfo{
-			Bank:     eStatement.Bank,
-			Produk:   eStatement.Produk,
-			Rekening: eStatement.Rekening,
-			Periode:  eStatement.Periode,
+			Bank:        eStatement.Bank,
+			Produk:      eStatement.Produk,
+			Rekening:    eStatement.Rekening,
+			Periode:     eStatement.Periode,
+			PeriodStart: eStatement.PeriodStart,
+			PeriodEnd:   eStatement.PeriodEnd,
+			HolderName:  eStatement.HolderName,
+			Branch:      eStatement.Branch,
+			Currency:    eStatement.Currency,
+			AccountType: eStatement.AccountType,
 		},
 		Metadata: metadata,
+		Warnings: eStatement.Warnings,
 	}
+	estatementscanner.Reconcile(&scanResult)
+	estatementscanner.Score(&scanResult)
 
 	return s.createScanEStatementResponse(
 		eStatement.ID,
@@ -145,6 +171,11 @@ func (s *EStatementService) processNewEStatement(
 		return nil, errors.New("transactions is empty")
 	}
 
+	if err := s.categorize(scanResult); err != nil {
+		s.log.Error("failed to categorize transactions", "err", err)
+		return nil, err
+	}
+
 	if payload.IsScanOnly() {
 		return s.createScanEStatementResponse(0, scanResult, &OverallSummary{}), nil
 	}
@@ -208,3 +239,14 @@ func (s *EStatementService) processNewEStatement(
 		summary,
 	), nil
 }
+
+// categorize assigns the category of every transaction
+func (s *EStatementService) categorize(scanResult *types.ScanResult) error {
+	c, err := s.categoryService.Categorizer()
+	if err != nil {
+		return err
+	}
+
+	c.Apply(scanResult)
+	return nil
+}
@@ -1,9 +1,13 @@
 package estatement
 
 import (
+	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/mrrizkin/omniscan/app/models"
+	"github.com/mrrizkin/omniscan/app/repositories"
+	"github.com/mrrizkin/omniscan/pkg/money"
 )
 
 type summaryField struct {
@@ -26,6 +30,7 @@ var allTimeFields = []string{
 	"FrequencyCredit", "TopDebits", "TopCredits", "AnomalyTransactions",
 	"TotalBankFee", "TotalInterest", "TotalTax", "TotalDigitalRevenue",
 	"TotalTransferIn", "TotalTransferOut", "TotalCashWithdrawal",
+	"TotalByCategory",
 }
 
 func skipFetch(field string, names []string) bool {
@@ -53,18 +58,24 @@ func skipFetch(field string, names []string) bool {
 }
 
 func (s *EStatementService) getSummary(eStatementID uint, names ...string) (*OverallSummary, error) {
+	repo, currency, err := s.summaryRepository(eStatementID)
+	if err != nil {
+		return nil, err
+	}
+
 	summary := &OverallSummary{
-		Monthly: MonthlySummary{},
-		AllTime: Summary{},
+		Currency: currency,
+		Monthly:  MonthlySummary{},
+		AllTime:  Summary{},
 	}
 
 	fields := []summaryField{
 		// AllTime fields
 		{
 			"StartBalance",
-			func() (interface{}, error) { return s.repo.GetBalance(eStatementID, "start") },
+			func() (interface{}, error) { return repo.GetBalance(eStatementID, "start") },
 			func(s *OverallSummary, v interface{}) {
-				startBalance, ok := v.(float64)
+				startBalance, ok := v.(money.Money)
 				if ok {
 					s.AllTime.StartBalance = startBalance
 				}
@@ -72,9 +83,9 @@ func (s *EStatementService) getSummary(eStatementID uint, names ...string) (*Ove
 		},
 		{
 			"AverageBalance",
-			func() (interface{}, error) { return s.repo.GetBalance(eStatementID, "avg") },
+			func() (interface{}, error) { return repo.GetBalance(eStatementID, "avg") },
 			func(s *OverallSummary, v interface{}) {
-				avgBalance, ok := v.(float64)
+				avgBalance, ok := v.(money.Money)
 				if ok {
 					s.AllTime.AverageBalance = avgBalance
 				}
@@ -82,9 +93,9 @@ func (s *EStatementService) getSummary(eStatementID uint, names ...string) (*Ove
 		},
 		{
 			"EndBalance",
-			func() (interface{}, error) { return s.repo.GetBalance(eStatementID, "end") },
+			func() (interface{}, error) { return repo.GetBalance(eStatementID, "end") },
 			func(s *OverallSummary, v interface{}) {
-				endBalance, ok := v.(float64)
+				endBalance, ok := v.(money.Money)
 				if ok {
 					s.AllTime.EndBalance = endBalance
 				}
@@ -93,10 +104,10 @@ func (s *EStatementService) getSummary(eStatementID uint, names ...string) (*Ove
 		{
 			"TotalIncome",
 			func() (interface{}, error) {
-				return s.repo.GetTransactionStatsByTransactionType(eStatementID, "credit", "total")
+				return repo.GetTransactionStatsByTransactionType(eStatementID, "credit", "total")
 			},
 			func(s *OverallSummary, v interface{}) {
-				totalIncome, ok := v.(float64)
+				totalIncome, ok := v.(money.Money)
 				if ok {
 					s.AllTime.TotalIncome = totalIncome
 				}
@@ -105,10 +116,10 @@ func (s *EStatementService) getSummary(eStatementID uint, names ...string) (*Ove
 		{
 			"TotalExpenses",
 			func() (interface{}, error) {
-				return s.repo.GetTransactionStatsByTransactionType(eStatementID, "debit", "total")
+				return repo.GetTransactionStatsByTransactionType(eStatementID, "debit", "total")
 			},
 			func(s *OverallSummary, v interface{}) {
-				totalExpenses, ok := v.(float64)
+				totalExpenses, ok := v.(money.Money)
 				if ok {
 					s.AllTime.TotalExpense = totalExpenses
 				}
@@ -117,10 +128,10 @@ func (s *EStatementService) getSummary(eStatementID uint, names ...string) (*Ove
 		{
 			"AverageDebit",
 			func() (interface{}, error) {
-				return s.repo.GetTransactionStatsByTransactionType(eStatementID, "debit", "avg")
+				return repo.GetTransactionStatsByTransactionType(eStatementID, "debit", "avg")
 			},
 			func(s *OverallSummary, v interface{}) {
-				avgDebit, ok := v.(float64)
+				avgDebit, ok := v.(money.Money)
 				if ok {
 					s.AllTime.AverageDebit = avgDebit
 				}
@@ -129,10 +140,10 @@ func (s *EStatementService) getSummary(eStatementID uint, names ...string) (*Ove
 		{
 			"AverageCredit",
 			func() (interface{}, error) {
-				return s.repo.GetTransactionStatsByTransactionType(eStatementID, "credit", "avg")
+				return repo.GetTransactionStatsByTransactionType(eStatementID, "credit", "avg")
 			},
 			func(s *OverallSummary, v interface{}) {
-				avgCredit, ok := v.(float64)
+				avgCredit, ok := v.(money.Money)
 				if ok {
 					s.AllTime.AverageCredit = avgCredit
 				}
@@ -141,10 +152,10 @@ func (s *EStatementService) getSummary(eStatementID uint, names ...string) (*Ove
 		{
 			"FrequencyDebit",
 			func() (interface{}, error) {
-				return s.repo.GetTransactionStatsByTransactionType(eStatementID, "debit", "count")
+				return repo.GetTransactionCountByTransactionType(eStatementID, "debit")
 			},
 			func(s *OverallSummary, v interface{}) {
-				freqDebit, ok := v.(float64)
+				freqDebit, ok := v.(int64)
 				if ok {
 					s.AllTime.FrequencyDebit = freqDebit
 				}
@@ -153,10 +164,10 @@ func (s *EStatementService) getSummary(eStatementID uint, names ...string) (*Ove
 		{
 			"FrequencyCredit",
 			func() (interface{}, error) {
-				return s.repo.GetTransactionStatsByTransactionType(eStatementID, "credit", "count")
+				return repo.GetTransactionCountByTransactionType(eStatementID, "credit")
 			},
 			func(s *OverallSummary, v interface{}) {
-				freqCredit, ok := v.(float64)
+				freqCredit, ok := v.(int64)
 				if ok {
 					s.AllTime.FrequencyCredit = freqCredit
 				}
@@ -165,7 +176,7 @@ func (s *EStatementService) getSummary(eStatementID uint, names ...string) (*Ove
 		{
 			"TopDebits",
 			func() (interface{}, error) {
-				return s.repo.GetTopChangeByTransactionType(eStatementID, "debit", 10)
+				return repo.GetTopChangeByTransactionType(eStatementID, "debit", 10)
 			},
 			func(s *OverallSummary, v interface{}) {
 				topDebits, ok := v.([]models.EStatementDetail)
@@ -177,7 +188,7 @@ func (s *EStatementService) getSummary(eStatementID uint, names ...string) (*Ove
 		{
 			"TopCredits",
 			func() (interface{}, error) {
-				return s.repo.GetTopChangeByTransactionType(eStatementID, "credit", 10)
+				return repo.GetTopChangeByTransactionType(eStatementID, "credit", 10)
 			},
 			func(s *OverallSummary, v interface{}) {
 				topCredits, ok := v.([]models.EStatementDetail)
@@ -189,7 +200,7 @@ func (s *EStatementService) getSummary(eStatementID uint, names ...string) (*Ove
 		{
 			"AnomalyTransactions",
 			func() (interface{}, error) {
-				return s.repo.GetAnomalyTransactions(eStatementID)
+				return repo.GetAnomalyTransactions(eStatementID)
 			},
 			func(s *OverallSummary, v interface{}) {
 				anomalyTransactions, ok := v.([]models.EStatementDetail)
@@ -201,10 +212,10 @@ func (s *EStatementService) getSummary(eStatementID uint, names ...string) (*Ove
 		{
 			"TotalBankFee",
 			func() (interface{}, error) {
-				return s.repo.GetTotalChangeByCategory(eStatementID, "bank_fee")
+				return repo.GetTotalChangeByCategory(eStatementID, "bank_fee")
 			},
 			func(s *OverallSummary, v interface{}) {
-				totalBankFee, ok := v.(float64)
+				totalBankFee, ok := v.(money.Money)
 				if ok {
 					s.AllTime.TotalBankFee = totalBankFee
 				}
@@ -213,10 +224,10 @@ func (s *EStatementService) getSummary(eStatementID uint, names ...string) (*Ove
 		{
 			"TotalInterest",
 			func() (interface{}, error) {
-				return s.repo.GetTotalChangeByCategory(eStatementID, "interest")
+				return repo.GetTotalChangeByCategory(eStatementID, "interest")
 			},
 			func(s *OverallSummary, v interface{}) {
-				totalInterest, ok := v.(float64)
+				totalInterest, ok := v.(money.Money)
 				if ok {
 					s.AllTime.TotalInterest = totalInterest
 				}
@@ -225,10 +236,10 @@ func (s *EStatementService) getSummary(eStatementID uint, names ...string) (*Ove
 		{
 			"TotalTax",
 			func() (interface{}, error) {
-				return s.repo.GetTotalChangeByCategory(eStatementID, "tax")
+				return repo.GetTotalChangeByCategory(eStatementID, "tax")
 			},
 			func(s *OverallSummary, v interface{}) {
-				totalTax, ok := v.(float64)
+				totalTax, ok := v.(money.Money)
 				if ok {
 					s.AllTime.TotalTax = totalTax
 				}
@@ -237,10 +248,10 @@ func (s *EStatementService) getSummary(eStatementID uint, names ...string) (*Ove
 		{
 			"TotalDigitalRevenue",
 			func() (interface{}, error) {
-				return s.repo.GetTotalChangeByCategory(eStatementID, "digital_revenue")
+				return repo.GetTotalChangeByCategory(eStatementID, "digital_revenue")
 			},
 			func(s *OverallSummary, v interface{}) {
-				totalDigitalRevenue, ok := v.(float64)
+				totalDigitalRevenue, ok := v.(money.Money)
 				if ok {
 					s.AllTime.TotalDigitalRevenue = totalDigitalRevenue
 				}
@@ -249,10 +260,10 @@ func (s *EStatementService) getSummary(eStatementID uint, names ...string) (*Ove
 		{
 			"TotalTransferIn",
 			func() (interface{}, error) {
-				return s.repo.GetTotalChangeByCategory(eStatementID, "transfer_in")
+				return repo.GetTotalChangeByCategory(eStatementID, "transfer_in")
 			},
 			func(s *OverallSummary, v interface{}) {
-				totalTransferIn, ok := v.(float64)
+				totalTransferIn, ok := v.(money.Money)
 				if ok {
 					s.AllTime.TotalTransferIn = totalTransferIn
 				}
@@ -261,10 +272,10 @@ func (s *EStatementService) getSummary(eStatementID uint, names ...string) (*Ove
 		{
 			"TotalTransferOut",
 			func() (interface{}, error) {
-				return s.repo.GetTotalChangeByCategory(eStatementID, "transfer_out")
+				return repo.GetTotalChangeByCategory(eStatementID, "transfer_out")
 			},
 			func(s *OverallSummary, v interface{}) {
-				totalTransferOut, ok := v.(float64)
+				totalTransferOut, ok := v.(money.Money)
 				if ok {
 					s.AllTime.TotalTransferOut = totalTransferOut
 				}
@@ -273,20 +284,32 @@ func (s *EStatementService) getSummary(eStatementID uint, names ...string) (*Ove
 		{
 			"TotalCashWithdrawal",
 			func() (interface{}, error) {
-				return s.repo.GetTotalChangeByCategory(eStatementID, "cash_withdrawal")
+				return repo.GetTotalChangeByCategory(eStatementID, "cash_withdrawal")
 			},
 			func(s *OverallSummary, v interface{}) {
-				totalCashWithdrawal, ok := v.(float64)
+				totalCashWithdrawal, ok := v.(money.Money)
 				if ok {
 					s.AllTime.TotalCashWithdrawal = totalCashWithdrawal
 				}
 			},
 		},
+		{
+			"TotalByCategory",
+			func() (interface{}, error) {
+				return repo.GetTotalChangeGroupByCategory(eStatementID)
+			},
+			func(s *OverallSummary, v interface{}) {
+				totalByCategory, ok := v.([]CategoryAmount)
+				if ok {
+					s.AllTime.TotalByCategory = totalByCategory
+				}
+			},
+		},
 
 		// Monthly fields
 		{
 			"MonthlyStartBalance",
-			func() (interface{}, error) { return s.repo.GetMonthlyBalances(eStatementID, "start") },
+			func() (interface{}, error) { return repo.GetMonthlyBalances(eStatementID, "start") },
 			func(s *OverallSummary, v interface{}) {
 				monthlyStartBalance, ok := v.([]MonthlyAmount)
 				if ok {
@@ -296,7 +319,7 @@ func (s *EStatementService) getSummary(eStatementID uint, names ...string) (*Ove
 		},
 		{
 			"MonthlyAverageBalance",
-			func() (interface{}, error) { return s.repo.GetMonthlyBalances(eStatementID, "avg") },
+			func() (interface{}, error) { return repo.GetMonthlyBalances(eStatementID, "avg") },
 			func(s *OverallSummary, v interface{}) {
 				monthlyAvgBalance, ok := v.([]MonthlyAmount)
 				if ok {
@@ -306,7 +329,7 @@ func (s *EStatementService) getSummary(eStatementID uint, names ...string) (*Ove
 		},
 		{
 			"MonthlyEndBalance",
-			func() (interface{}, error) { return s.repo.GetMonthlyBalances(eStatementID, "end") },
+			func() (interface{}, error) { return repo.GetMonthlyBalances(eStatementID, "end") },
 			func(s *OverallSummary, v interface{}) {
 				monthlyEndBalance, ok := v.([]MonthlyAmount)
 				if ok {
@@ -317,7 +340,7 @@ func (s *EStatementService) getSummary(eStatementID uint, names ...string) (*Ove
 		{
 			"MonthlyTotalIncome",
 			func() (interface{}, error) {
-				return s.repo.GetMonthlyTransactionStatsByTransactionType(
+				return repo.GetMonthlyTransactionStatsByTransactionType(
 					eStatementID,
 					"credit",
 					"total",
@@ -333,7 +356,7 @@ func (s *EStatementService) getSummary(eStatementID uint, names ...string) (*Ove
 		{
 			"MonthlyTotalExpenses",
 			func() (interface{}, error) {
-				return s.repo.GetMonthlyTransactionStatsByTransactionType(
+				return repo.GetMonthlyTransactionStatsByTransactionType(
 					eStatementID,
 					"debit",
 					"total",
@@ -349,7 +372,7 @@ func (s *EStatementService) getSummary(eStatementID uint, names ...string) (*Ove
 		{
 			"MonthlyAverageDebit",
 			func() (interface{}, error) {
-				return s.repo.GetMonthlyTransactionStatsByTransactionType(eStatementID, "debit", "avg")
+				return repo.GetMonthlyTransactionStatsByTransactionType(eStatementID, "debit", "avg")
 			},
 			func(s *OverallSummary, v interface{}) {
 				monthlyAvgDebit, ok := v.([]MonthlyAmount)
@@ -361,7 +384,7 @@ func (s *EStatementService) getSummary(eStatementID uint, names ...string) (*Ove
 		{
 			"MonthlyAverageCredit",
 			func() (interface{}, error) {
-				return s.repo.GetMonthlyTransactionStatsByTransactionType(eStatementID, "credit", "avg")
+				return repo.GetMonthlyTransactionStatsByTransactionType(eStatementID, "credit", "avg")
 			},
 			func(s *OverallSummary, v interface{}) {
 				monthlyAvgCredit, ok := v.([]MonthlyAmount)
@@ -373,14 +396,10 @@ func (s *EStatementService) getSummary(eStatementID uint, names ...string) (*Ove
 		{
 			"MonthlyFrequencyDebit",
 			func() (interface{}, error) {
-				return s.repo.GetMonthlyTransactionStatsByTransactionType(
-					eStatementID,
-					"debit",
-					"count",
-				)
+				return repo.GetMonthlyTransactionCountByTransactionType(eStatementID, "debit")
 			},
 			func(s *OverallSummary, v interface{}) {
-				monthlyFreqDebit, ok := v.([]MonthlyAmount)
+				monthlyFreqDebit, ok := v.([]MonthlyCount)
 				if ok {
 					s.Monthly.FrequencyDebit = monthlyFreqDebit
 				}
@@ -389,14 +408,10 @@ func (s *EStatementService) getSummary(eStatementID uint, names ...string) (*Ove
 		{
 			"MonthlyFrequencyCredit",
 			func() (interface{}, error) {
-				return s.repo.GetMonthlyTransactionStatsByTransactionType(
-					eStatementID,
-					"credit",
-					"count",
-				)
+				return repo.GetMonthlyTransactionCountByTransactionType(eStatementID, "credit")
 			},
 			func(s *OverallSummary, v interface{}) {
-				monthlyFreqCredit, ok := v.([]MonthlyAmount)
+				monthlyFreqCredit, ok := v.([]MonthlyCount)
 				if ok {
 					s.Monthly.FrequencyCredit = monthlyFreqCredit
 				}
@@ -405,7 +420,7 @@ func (s *EStatementService) getSummary(eStatementID uint, names ...string) (*Ove
 		{
 			"MonthlyTopDebits",
 			func() (interface{}, error) {
-				return s.repo.GetMonthlyTopChangeByTransactionType(eStatementID, "debit", 10)
+				return repo.GetMonthlyTopChangeByTransactionType(eStatementID, "debit", 10)
 			},
 			func(s *OverallSummary, v interface{}) {
 				monthlyTopDebits, ok := v.([]MonthlyEStatementDetails)
@@ -417,7 +432,7 @@ func (s *EStatementService) getSummary(eStatementID uint, names ...string) (*Ove
 		{
 			"MonthlyTopCredits",
 			func() (interface{}, error) {
-				return s.repo.GetMonthlyTopChangeByTransactionType(eStatementID, "credit", 10)
+				return repo.GetMonthlyTopChangeByTransactionType(eStatementID, "credit", 10)
 			},
 			func(s *OverallSummary, v interface{}) {
 				monthlyTopCredits, ok := v.([]MonthlyEStatementDetails)
@@ -461,3 +476,30 @@ func (s *EStatementService) getSummary(eStatementID uint, names ...string) (*Ove
 
 	return summary, nil
 }
+
+// summaryRepository returns the repository used to add up the amounts of a
+// statement and the currency of the totals. Statements mixing currencies
+// are converted into the base currency, they are refused when a currency
+// has no configured rate.
+func (s *EStatementService) summaryRepository(
+	eStatementID uint,
+) (*repositories.EStatementRepository, string, error) {
+	currencies, err := s.repo.GetCurrencies(eStatementID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	switch {
+	case len(currencies) == 0:
+		return s.repo, "", nil
+	case len(currencies) == 1:
+		return s.repo, currencies[0], nil
+	case s.rates.Covers(currencies):
+		return s.repo.WithRates(s.rates), s.rates.Base, nil
+	}
+
+	return nil, "", fmt.Errorf(
+		"can't add up amounts in %s without exchange rates",
+		strings.Join(currencies, ", "),
+	)
+}
@@ -1,10 +1,10 @@
 package estatement
 
 import (
-	"time"
-
 	"github.com/mrrizkin/omniscan/app/models"
+	"github.com/mrrizkin/omniscan/app/repositories"
 	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+	"github.com/mrrizkin/omniscan/pkg/money"
 )
 
 type PaginatedEStatement struct {
@@ -13,7 +13,7 @@ type PaginatedEStatement struct {
 }
 
 type ScanEStatementPayload struct {
-	PDFLib   string  `form:"pdf_library"  validate:"required"`
+	PDFLib   string  `form:"pdf_library"`
 	Bank     string  `form:"bank"`
 	TimeBomb *string `form:"time_bomb"`
 	Summary  string  `form:"summary"`
@@ -36,31 +36,33 @@ type ScanEStatementResponse struct {
 }
 
 type Summary struct {
-	StartBalance   float64 `json:"start_balance,omitempty"`
-	AverageBalance float64 `json:"average_balance,omitempty"`
-	EndBalance     float64 `json:"end_balance,omitempty"`
+	StartBalance   money.Money `json:"start_balance,omitempty"`
+	AverageBalance money.Money `json:"average_balance,omitempty"`
+	EndBalance     money.Money `json:"end_balance,omitempty"`
 
-	TotalIncome  float64 `json:"total_income,omitempty"`
-	TotalExpense float64 `json:"total_expense,omitempty"`
+	TotalIncome  money.Money `json:"total_income,omitempty"`
+	TotalExpense money.Money `json:"total_expense,omitempty"`
 
 	TopDebits  []models.EStatementDetail `json:"top_debits,omitempty"`
 	TopCredits []models.EStatementDetail `json:"top_credits,omitempty"`
 
 	AnomalyTransactions []models.EStatementDetail `json:"anomaly_transactions,omitempty"`
 
-	TotalBankFee        float64 `json:"total_bank_fee,omitempty"`
-	TotalInterest       float64 `json:"total_interest,omitempty"`
-	TotalTax            float64 `json:"total_tax,omitempty"`
-	TotalDigitalRevenue float64 `json:"total_digital_revenue,omitempty"`
-	TotalTransferIn     float64 `json:"total_transfer_in,omitempty"`
-	TotalTransferOut    float64 `json:"total_transfer_out,omitempty"`
-	TotalCashWithdrawal float64 `json:"total_cash_withdrawal,omitempty"`
+	TotalBankFee        money.Money `json:"total_bank_fee,omitempty"`
+	TotalInterest       money.Money `json:"total_interest,omitempty"`
+	TotalTax            money.Money `json:"total_tax,omitempty"`
+	TotalDigitalRevenue money.Money `json:"total_digital_revenue,omitempty"`
+	TotalTransferIn     money.Money `json:"total_transfer_in,omitempty"`
+	TotalTransferOut    money.Money `json:"total_transfer_out,omitempty"`
+	TotalCashWithdrawal money.Money `json:"total_cash_withdrawal,omitempty"`
+
+	TotalByCategory []CategoryAmount `json:"total_by_category,omitempty"`
 
-	AverageCredit float64 `json:"average_credit,omitempty"`
-	AverageDebit  float64 `json:"average_debit,omitempty"`
+	AverageCredit money.Money `json:"average_credit,omitempty"`
+	AverageDebit  money.Money `json:"average_debit,omitempty"`
 
-	FrequencyDebit  float64 `json:"frequency_debit,omitempty"`
-	FrequencyCredit float64 `json:"frequency_credit,omitempty"`
+	FrequencyDebit  int64 `json:"frequency_debit,omitempty"`
+	FrequencyCredit int64 `json:"frequency_credit,omitempty"`
 }
 
 type MonthlySummary struct {
@@ -77,13 +79,14 @@ type MonthlySummary struct {
 	AverageCredit []MonthlyAmount `json:"average_credit,omitempty"`
 	AverageDebit  []MonthlyAmount `json:"average_debit,omitempty"`
 
-	FrequencyDebit  []MonthlyAmount `json:"frequency_debit,omitempty"`
-	FrequencyCredit []MonthlyAmount `json:"frequency_credit,omitempty"`
+	FrequencyDebit  []MonthlyCount `json:"frequency_debit,omitempty"`
+	FrequencyCredit []MonthlyCount `json:"frequency_credit,omitempty"`
 }
 
 type OverallSummary struct {
-	AllTime Summary        `json:"all_time,omitempty"`
-	Monthly MonthlySummary `json:"monthly,omitempty"`
+	Currency string         `json:"currency,omitempty"`
+	AllTime  Summary        `json:"all_time,omitempty"`
+	Monthly  MonthlySummary `json:"monthly,omitempty"`
 }
 
 type Meta struct {
@@ -103,12 +106,9 @@ type Meta struct {
 	PDFVersion   string `json:"pdf_version"`
 }
 
-type MonthlyAmount struct {
-	Date   time.Time `json:"date"`
-	Amount float64   `json:"amount"`
-}
-
-type MonthlyEStatementDetails struct {
-	Date   time.Time                 `json:"date"`
-	Detail []models.EStatementDetail `json:"e_statement_details"`
-}
+type (
+	MonthlyAmount            = repositories.MonthlyAmount
+	MonthlyCount             = repositories.MonthlyCount
+	MonthlyEStatementDetails = repositories.MonthlyEStatementDetails
+	CategoryAmount           = repositories.CategoryAmount
+)
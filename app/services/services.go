@@ -3,6 +3,7 @@ package services
 import (
 	"go.uber.org/fx"
 
+	"github.com/mrrizkin/omniscan/app/services/category"
 	estatement "github.com/mrrizkin/omniscan/app/services/e-statement"
 	"github.com/mrrizkin/omniscan/pkg/boot/constructor"
 )
@@ -11,5 +12,6 @@ func New() fx.Option {
 	return constructor.Load(
 		&UserService{},
 		&estatement.EStatementService{},
+		&category.CategoryService{},
 	)
 }
@@ -16,8 +16,20 @@ import (
 	"github.com/mrrizkin/omniscan/app/providers/scheduler"
 	"github.com/mrrizkin/omniscan/app/repositories"
 	"github.com/mrrizkin/omniscan/app/services"
+	"github.com/mrrizkin/omniscan/app/services/category"
 	"github.com/mrrizkin/omniscan/config"
+	"github.com/mrrizkin/omniscan/pkg/categorizer"
 	estatementscanner "github.com/mrrizkin/omniscan/pkg/e-statement-scanner"
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/bca"
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/bni"
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/bri"
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/bsi"
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/cimb"
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/mandiri"
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/template"
+	"github.com/mrrizkin/omniscan/pkg/money"
+	"github.com/mrrizkin/omniscan/pkg/pdf/provider/pdfcpu"
+	"github.com/mrrizkin/omniscan/pkg/pdf/provider/rscpdf"
 	"github.com/mrrizkin/omniscan/routes"
 )
 
@@ -32,12 +44,15 @@ func App() *fx.App {
 		services.New(),
 
 		// deps | pkg
-		fx.Provide(estatementscanner.New),
+		fx.Provide(newEStatementScanner),
+		fx.Provide(newExchangeRates),
+		fx.Provide(newCategoryRules),
 
 		fx.Invoke(
 			app.Boot,
 			console.Schedule,
 			models.AutoMigrate,
+			seedCategories,
 			routes.ApiRoutes,
 			routes.WebRoutes,
 			startScheduler,
@@ -57,6 +72,58 @@ func startScheduler(scheduler *scheduler.Scheduler) {
 	scheduler.Start()
 }
 
+func newEStatementScanner(
+	cfg *config.Scanner,
+	log *logger.Logger,
+) (*estatementscanner.EStatementScanner, error) {
+	scanner := estatementscanner.New()
+
+	scanner.RegisterLibrary("pdfcpu", pdfcpu.Open)
+	scanner.RegisterLibrary("rscpdf", rscpdf.Open)
+	scanner.SetLibraryOrder(cfg.LibraryOrder())
+
+	scanner.RegisterBank(bca.New())
+	scanner.RegisterBank(mandiri.New())
+	scanner.RegisterBank(bni.New())
+	scanner.RegisterBank(bri.New())
+	scanner.RegisterBank(bsi.New())
+	scanner.RegisterBank(cimb.New())
+
+	// a template adds a bank layout without a rebuild, it cannot take the
+	// identifier of a bank package
+	templates, err := template.LoadDir(cfg.TEMPLATE_DIR)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range templates {
+		if _, ok := scanner.Bank(t.ID()); ok {
+			log.Warn("e-statement template skipped, the bank is already registered", "bank", t.ID())
+			continue
+		}
+		log.Info("registering e-statement template", "bank", t.ID())
+		scanner.RegisterBank(t)
+	}
+
+	return scanner, nil
+}
+
+// newExchangeRates loads the rate table used to add up statements mixing
+// currencies
+func newExchangeRates(cfg *config.Currency) (*money.Rates, error) {
+	return money.LoadRates(cfg.BASE, cfg.RATE_FILE)
+}
+
+// newCategoryRules loads the category rules applied after the stored ones
+func newCategoryRules(cfg *config.Category) (categorizer.Rules, error) {
+	return categorizer.LoadFile(cfg.RULE_FILE)
+}
+
+// seedCategories runs after the migration so the categories of the
+// configured rules exist in the database
+func seedCategories(categories *category.CategoryService) error {
+	return categories.Seed()
+}
+
 func useLogger(logger *logger.Logger) fxevent.Logger {
 	return logger
 }
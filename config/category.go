@@ -0,0 +1,13 @@
+package config
+
+type Category struct {
+	RULE_FILE string `env:"CATEGORY_RULE_FILE"`
+}
+
+func (*Category) Construct() interface{} {
+	return func() (*Category, error) {
+		var category Category
+		err := load(&category)
+		return &category, err
+	}
+}
@@ -11,5 +11,8 @@ func New() fx.Option {
 		&App{},
 		&Database{},
 		&Session{},
+		&Scanner{},
+		&Currency{},
+		&Category{},
 	)
 }
@@ -0,0 +1,14 @@
+package config
+
+type Currency struct {
+	BASE      string `env:"CURRENCY_BASE,default=IDR"`
+	RATE_FILE string `env:"CURRENCY_RATE_FILE"`
+}
+
+func (*Currency) Construct() interface{} {
+	return func() (*Currency, error) {
+		var currency Currency
+		err := load(&currency)
+		return &currency, err
+	}
+}
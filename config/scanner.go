@@ -0,0 +1,24 @@
+package config
+
+import "strings"
+
+type Scanner struct {
+	PDF_LIBRARY_ORDER string `env:"SCANNER_PDF_LIBRARY_ORDER"`
+	TEMPLATE_DIR      string `env:"SCANNER_TEMPLATE_DIR"`
+}
+
+func (*Scanner) Construct() interface{} {
+	return func() (*Scanner, error) {
+		var scanner Scanner
+		err := load(&scanner)
+		return &scanner, err
+	}
+}
+
+// LibraryOrder splits SCANNER_PDF_LIBRARY_ORDER, libraries can be separated
+// by comma or space.
+func (s *Scanner) LibraryOrder() []string {
+	return strings.FieldsFunc(s.PDF_LIBRARY_ORDER, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+}
@@ -0,0 +1,157 @@
+package categorizer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+	"github.com/mrrizkin/omniscan/pkg/money"
+)
+
+// Rule assigns Category to the transactions matching every condition it
+// sets, empty conditions match everything. Description is a regular
+// expression matched against both description columns joined by a space,
+// the amount range is inclusive.
+type Rule struct {
+	Category    string       `json:"category"`
+	Bank        string       `json:"bank,omitempty"`
+	Description string       `json:"description,omitempty"`
+	Direction   string       `json:"direction,omitempty"`
+	MinAmount   *money.Money `json:"min_amount,omitempty"`
+	MaxAmount   *money.Money `json:"max_amount,omitempty"`
+}
+
+type Rules []Rule
+
+// DefaultRules are used when no rule is configured, they replace the
+// categories that used to be hardcoded in the summary queries.
+var DefaultRules = Rules{
+	{Category: "tax", Description: `(?i)PAJAK|\bTAX\b`},
+	{Category: "interest", Description: `(?i)^(BUNGA|BAGI HASIL|INTEREST)`, Direction: "credit"},
+	{Category: "bank_fee", Description: `(?i)BIAYA|^ADM\b|ADMINISTRASI|\bFEE\b`, Direction: "debit"},
+	{Category: "cash_withdrawal", Description: `(?i)TARIK(AN)? (TUNAI|ATM)`, Direction: "debit"},
+	{Category: "digital_revenue", Description: `(?i)ESPAY DEBIT`, Direction: "credit"},
+	{Category: "transfer_in", Description: `(?i)TRSF|TRANSFER|\bTRF\b|SWITCHING|BI-FAST`, Direction: "credit"},
+	{Category: "transfer_out", Description: `(?i)TRSF|TRANSFER|\bTRF\b|SWITCHING|BI-FAST|\bBYR\b`, Direction: "debit"},
+}
+
+// LoadFile reads the rules from a JSON array, an empty filename gives the
+// default rules.
+func LoadFile(filename string) (Rules, error) {
+	if filename == "" {
+		return DefaultRules, nil
+	}
+
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules Rules
+	if err := json.Unmarshal(b, &rules); err != nil {
+		return nil, fmt.Errorf("invalid category rule file %s: %w", filename, err)
+	}
+
+	if _, err := New(rules); err != nil {
+		return nil, fmt.Errorf("invalid category rule file %s: %w", filename, err)
+	}
+
+	return rules, nil
+}
+
+type compiledRule struct {
+	Rule
+	description *regexp.Regexp
+}
+
+// Categorizer assigns the category of the first matching rule, the order
+// of the rules is the order they were given.
+type Categorizer struct {
+	rules []compiledRule
+}
+
+func New(rules Rules) (*Categorizer, error) {
+	c := &Categorizer{rules: make([]compiledRule, len(rules))}
+	for i, rule := range rules {
+		compiled, err := rule.compile()
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i+1, err)
+		}
+		c.rules[i] = compiled
+	}
+	return c, nil
+}
+
+// Validate checks the rule can be compiled.
+func (r Rule) Validate() error {
+	_, err := r.compile()
+	return err
+}
+
+func (r Rule) compile() (compiledRule, error) {
+	if r.Category == "" {
+		return compiledRule{}, fmt.Errorf("category is required")
+	}
+
+	switch r.Direction {
+	case "", "credit", "debit":
+	default:
+		return compiledRule{}, fmt.Errorf("unknown direction: %s", r.Direction)
+	}
+
+	if r.MinAmount != nil && r.MaxAmount != nil && *r.MinAmount > *r.MaxAmount {
+		return compiledRule{}, fmt.Errorf("min_amount is greater than max_amount")
+	}
+
+	compiled := compiledRule{Rule: r}
+	if r.Description != "" {
+		description, err := regexp.Compile(r.Description)
+		if err != nil {
+			return compiledRule{}, fmt.Errorf("invalid description: %w", err)
+		}
+		compiled.description = description
+	}
+
+	return compiled, nil
+}
+
+// Categorize returns the category of t, empty when no rule matches. bank
+// is the bank name of the statement, compared ignoring case.
+func (c *Categorizer) Categorize(bank string, t *types.Transaction) string {
+	description := strings.Join(strings.Fields(t.Description1+" "+t.Description2), " ")
+	for _, rule := range c.rules {
+		if rule.matches(bank, description, t) {
+			return rule.Category
+		}
+	}
+	return ""
+}
+
+// Apply sets the category of every transaction of result.
+func (c *Categorizer) Apply(result *types.ScanResult) {
+	for _, t := range result.Transactions {
+		t.Category = c.Categorize(result.Info.Bank, t)
+	}
+}
+
+func (r compiledRule) matches(bank, description string, t *types.Transaction) bool {
+	if r.Bank != "" && !strings.EqualFold(r.Bank, bank) {
+		return false
+	}
+	if r.Direction != "" && r.Direction != t.TransactionType {
+		return false
+	}
+	if r.MinAmount != nil && t.Change < *r.MinAmount {
+		return false
+	}
+	if r.MaxAmount != nil && t.Change > *r.MaxAmount {
+		return false
+	}
+	if r.description != nil && !r.description.MatchString(description) {
+		return false
+	}
+	return true
+}
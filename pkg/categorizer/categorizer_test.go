@@ -0,0 +1,207 @@
+package categorizer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+	"github.com/mrrizkin/omniscan/pkg/money"
+)
+
+func amount(i int64) *money.Money {
+	m := money.FromInt(i)
+	return &m
+}
+
+func transaction(description1, description2, transactionType string, change int64) *types.Transaction {
+	return &types.Transaction{
+		Description1:    description1,
+		Description2:    description2,
+		TransactionType: transactionType,
+		Change:          money.FromInt(change),
+	}
+}
+
+func TestDefaultRules(t *testing.T) {
+	tests := []struct {
+		name string
+		bank string
+		t    *types.Transaction
+		want string
+	}{
+		{name: "bca e-banking credit", bank: "BCA", t: transaction("TRSF E-BANKING CR\n1234/FTSCY/WS95031\nJOHN DOE", "", "credit", 250000), want: "transfer_in"},
+		{name: "bca switching debit", bank: "BCA", t: transaction("SWITCHING DB", "TRANSFER KE 014 JANE DOE", "debit", 100000), want: "transfer_out"},
+		{name: "bca admin fee", bank: "BCA", t: transaction("BIAYA ADM", "", "debit", 10000), want: "bank_fee"},
+		{name: "bca atm withdrawal", bank: "BCA", t: transaction("TARIKAN ATM 01/02", "", "debit", 500000), want: "cash_withdrawal"},
+		{name: "bca interest", bank: "BCA", t: transaction("BUNGA", "", "credit", 1234), want: "interest"},
+		{name: "bca tax on interest", bank: "BCA", t: transaction("PAJAK BUNGA", "", "debit", 246), want: "tax"},
+		{name: "bca espay", bank: "BCA", t: transaction("TRSF E-BANKING CR", "ESPAY DEBIT INDONE", "credit", 75000), want: "digital_revenue"},
+		{name: "mandiri transfer in", bank: "Mandiri", t: transaction("Transfer dari BUDI SANTOSO", "", "credit", 150000), want: "transfer_in"},
+		{name: "mandiri admin fee", bank: "Mandiri", t: transaction("Biaya Adm", "", "debit", 12500), want: "bank_fee"},
+		{name: "mandiri atm withdrawal", bank: "Mandiri", t: transaction("Tarik Tunai ATM", "ATM MANDIRI SUDIRMAN", "debit", 300000), want: "cash_withdrawal"},
+		{name: "mandiri interest", bank: "Mandiri", t: transaction("Bunga", "", "credit", 2100), want: "interest"},
+		{name: "bri bi-fast credit", bank: "BRI", t: transaction("BI-FAST CR 014 JOHN DOE", "", "credit", 200000), want: "transfer_in"},
+		{name: "bri bill payment", bank: "BRI", t: transaction("BRIVA BYR TAGIHAN PLN", "", "debit", 350000), want: "transfer_out"},
+		{name: "bri mobile banking", bank: "BRI", t: transaction("NBMB JOHN DOE TO JANE DOE", "", "debit", 50000)},
+		{name: "interest on a debit", bank: "BRI", t: transaction("BUNGA", "", "debit", 1000)},
+	}
+
+	c, err := New(DefaultRules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.Categorize(tt.bank, tt.t); got != tt.want {
+				t.Errorf("Categorize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCategorizeOrder(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules Rules
+		bank  string
+		want  string
+	}{
+		{
+			name:  "first matching rule",
+			rules: Rules{{Category: "salary", Description: `GAJI`}, {Category: "transfer_in", Description: `TRANSFER`}},
+			bank:  "BCA",
+			want:  "salary",
+		},
+		{
+			name:  "order of the rules",
+			rules: Rules{{Category: "transfer_in", Description: `TRANSFER`}, {Category: "salary", Description: `GAJI`}},
+			bank:  "BCA",
+			want:  "transfer_in",
+		},
+		{
+			name:  "rule of another bank is skipped",
+			rules: Rules{{Category: "salary", Bank: "Mandiri"}, {Category: "transfer_in"}},
+			bank:  "BCA",
+			want:  "transfer_in",
+		},
+		{
+			name:  "bank ignoring case",
+			rules: Rules{{Category: "salary", Bank: "bca"}, {Category: "transfer_in"}},
+			bank:  "BCA",
+			want:  "salary",
+		},
+		{
+			name:  "no matching rule",
+			rules: Rules{{Category: "salary", Description: `PAYROLL`}},
+			bank:  "BCA",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := New(tt.rules)
+			if err != nil {
+				t.Fatal(err)
+			}
+			trx := transaction("TRANSFER GAJI", "PT MAJU JAYA", "credit", 5000000)
+			if got := c.Categorize(tt.bank, trx); got != tt.want {
+				t.Errorf("Categorize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCategorizeFilters(t *testing.T) {
+	tests := []struct {
+		name string
+		rule Rule
+		t    *types.Transaction
+		want bool
+	}{
+		{name: "credit rule on a credit", rule: Rule{Direction: "credit"}, t: transaction("SETORAN", "", "credit", 100), want: true},
+		{name: "debit rule on a credit", rule: Rule{Direction: "debit"}, t: transaction("SETORAN", "", "credit", 100)},
+		{name: "direction rule on the opening balance", rule: Rule{Direction: "credit"}, t: transaction("SALDO AWAL", "", "", 0)},
+		{name: "below min amount", rule: Rule{MinAmount: amount(1000)}, t: transaction("SETORAN", "", "credit", 999)},
+		{name: "at min amount", rule: Rule{MinAmount: amount(1000)}, t: transaction("SETORAN", "", "credit", 1000), want: true},
+		{name: "at max amount", rule: Rule{MaxAmount: amount(1000)}, t: transaction("SETORAN", "", "credit", 1000), want: true},
+		{name: "above max amount", rule: Rule{MaxAmount: amount(1000)}, t: transaction("SETORAN", "", "credit", 1001)},
+		{name: "within the range", rule: Rule{MinAmount: amount(100), MaxAmount: amount(1000)}, t: transaction("SETORAN", "", "debit", 500), want: true},
+		{name: "description across both columns", rule: Rule{Description: `^SETORAN TUNAI$`}, t: transaction("SETORAN", "  TUNAI", "credit", 100), want: true},
+		{name: "description not matching", rule: Rule{Description: `GAJI`}, t: transaction("SETORAN", "", "credit", 100)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.rule.Category = "matched"
+			c, err := New(Rules{tt.rule})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := c.Categorize("BCA", tt.t) == "matched"; got != tt.want {
+				t.Errorf("Categorize() matched = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    Rules
+		wantErr string
+	}{
+		{
+			name:    "valid rules",
+			content: `[{"category": "salary", "description": "GAJI", "direction": "credit", "min_amount": "1000000"}]`,
+			want:    Rules{{Category: "salary", Description: "GAJI", Direction: "credit", MinAmount: amount(1000000)}},
+		},
+		{name: "invalid json", content: `{"category": "salary"}`, wantErr: "invalid category rule file"},
+		{name: "missing category", content: `[{"description": "GAJI"}]`, wantErr: "rule 1: category is required"},
+		{name: "unknown direction", content: `[{"category": "salary"}, {"category": "fee", "direction": "out"}]`, wantErr: "rule 2: unknown direction: out"},
+		{name: "min above max", content: `[{"category": "salary", "min_amount": "2000", "max_amount": "1000"}]`, wantErr: "min_amount is greater than max_amount"},
+		{name: "invalid description", content: `[{"category": "salary", "description": "GAJI("}]`, wantErr: "invalid description"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "rules.json")
+			if err := os.WriteFile(filename, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := LoadFile(filename)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadFile() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadFile() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("no file", func(t *testing.T) {
+		got, err := LoadFile("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, DefaultRules) {
+			t.Errorf("LoadFile() = %+v, want the default rules", got)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := LoadFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+			t.Error("LoadFile() error = nil, want an error")
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package estatementscanner
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+	"github.com/mrrizkin/omniscan/pkg/pdf"
+)
+
+// SetLibraryOrder sets the order the libraries are tried in auto mode,
+// libraries that are not listed are tried last in registration order.
+func (ms *EStatementScanner) SetLibraryOrder(order []string) {
+	ms.libraryOrder = order
+}
+
+func (ms *EStatementScanner) autoLibraries() []string {
+	libraries := make([]string, 0, len(ms.libraries))
+	for _, id := range ms.libraryOrder {
+		if ms.hasLibrary(id) && !inArray(id, libraries) {
+			libraries = append(libraries, id)
+		}
+	}
+	for _, lib := range ms.libraries {
+		if !inArray(lib.id, libraries) {
+			libraries = append(libraries, lib.id)
+		}
+	}
+	return libraries
+}
+
+// scanAuto scans the document with every library and keeps the result
+// with the best balance reconciliation, the transaction count breaks ties
+func (ms *EStatementScanner) scanAuto(
+	scanner types.BankScanner,
+	filename string,
+	input []byte,
+	metadata *pdf.Metadata,
+) (*types.ScanResult, error) {
+	libraries := ms.autoLibraries()
+	attempts := make([]types.LibraryAttempt, len(libraries))
+	results := make([]*types.ScanResult, len(libraries))
+
+	best := -1
+	for i, library := range libraries {
+		attempts[i].Library = library
+
+		result, err := ms.scan(scanner, library, filename, input, metadata)
+		if err != nil {
+			attempts[i].Reason = err.Error()
+			continue
+		}
+
+		attempts[i].Transactions = len(result.Transactions)
+		attempts[i].Reconciled = result.Integrity
+		if attempts[i].Transactions == 0 {
+			attempts[i].Reason = "no transactions found"
+			continue
+		}
+
+		results[i] = result
+		if best == -1 || isBetterAttempt(attempts[i], attempts[best]) {
+			best = i
+		}
+	}
+
+	if best == -1 {
+		return nil, errors.New("no pdf library could scan the e-statement")
+	}
+
+	for i := range attempts {
+		if i == best {
+			attempts[i].Selected = true
+			continue
+		}
+
+		if results[i] != nil {
+			attempts[i].Reason = fmt.Sprintf(
+				"%s scored better (%d transactions, %.2f reconciled)",
+				attempts[best].Library,
+				attempts[best].Transactions,
+				attempts[best].Reconciled,
+			)
+		}
+	}
+
+	result := results[best]
+	result.Info.LibraryAttempts = attempts
+	return result, nil
+}
+
+func isBetterAttempt(a, b types.LibraryAttempt) bool {
+	if a.Reconciled != b.Reconciled {
+		return a.Reconciled > b.Reconciled
+	}
+	return a.Transactions > b.Transactions
+}
+
+func inArray(needle string, haystack []string) bool {
+	for _, item := range haystack {
+		if item == needle {
+			return true
+		}
+	}
+	return false
+}
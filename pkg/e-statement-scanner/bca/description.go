@@ -0,0 +1,70 @@
+package bca
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+)
+
+var (
+	// e-banking reference, e.g. 1234/FTSCY/WS95031
+	referenceRegex = regexp.MustCompile(`^\d{4}/[A-Z]+/[A-Z0-9]+$`)
+	// the amount is repeated in the description of transfers
+	amountLineRegex = regexp.MustCompile(`^[\d,]+\.\d{2}$`)
+	// BI-FAST and switching transfers, e.g. "BIF TRANSFER DR 014 JOHN DOE"
+	transferRegex = regexp.MustCompile(`^(?:BIF )?TRANSFER (?:DR|KE)\s*(\d{3})?\s*(.*)$`)
+	// the bank code and name printed on the line after "BIF TRANSFER DR"
+	bankCodeRegex = regexp.MustCompile(`^(\d{3}) (\D.*)$`)
+	accountRegex  = regexp.MustCompile(`^\d{10,16}$`)
+)
+
+// readDescription fills the structured fields of t from the KETERANGAN
+// lines, the first line is the kind of transaction, e.g. "TRSF E-BANKING CR"
+func readDescription(t *types.Transaction) {
+	lines := types.DescriptionLines(t.Description1, t.Description2)
+	if len(lines) == 0 {
+		return
+	}
+
+	kind := lines[0]
+	switch {
+	case strings.HasPrefix(kind, "TRSF E-BANKING"):
+		t.Channel = types.ChannelEBanking
+	case strings.HasPrefix(kind, "BI-FAST"):
+		t.Channel = types.ChannelBIFast
+	case strings.HasPrefix(kind, "SWITCHING"):
+		t.Channel = types.ChannelSwitching
+	case strings.Contains(kind, "ATM"):
+		t.Channel = types.ChannelATM
+	}
+
+	remark := make([]string, 0)
+	for _, line := range lines[1:] {
+		switch {
+		case t.Reference == "" && referenceRegex.MatchString(line):
+			t.Reference = line
+		case amountLineRegex.MatchString(line):
+			// already read from the MUTASI column
+		case strings.HasPrefix(line, "QR "):
+			t.Channel = types.ChannelQRIS
+		case t.CounterpartyName == "" && transferRegex.MatchString(line):
+			match := transferRegex.FindStringSubmatch(line)
+			t.CounterpartyBank, t.CounterpartyName = match[1], match[2]
+		case t.CounterpartyBank == "" && t.Channel != "" && bankCodeRegex.MatchString(line):
+			match := bankCodeRegex.FindStringSubmatch(line)
+			t.CounterpartyBank, t.CounterpartyName = match[1], match[2]
+		case t.CounterpartyAccount == "" && accountRegex.MatchString(line):
+			t.CounterpartyAccount = line
+		default:
+			remark = append(remark, line)
+		}
+	}
+
+	// transfers and QRIS payments end with the counterparty or merchant
+	if t.CounterpartyName == "" && t.Channel != "" && t.Channel != types.ChannelATM && len(remark) > 0 {
+		t.CounterpartyName = remark[len(remark)-1]
+		remark = remark[:len(remark)-1]
+	}
+	t.Remark = strings.Join(remark, " ")
+}
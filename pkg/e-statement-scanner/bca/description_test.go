@@ -0,0 +1,82 @@
+package bca
+
+import (
+	"testing"
+
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/scannertest"
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+)
+
+func TestReadDescription(t *testing.T) {
+	tests := []struct {
+		name            string
+		description1    string
+		description2    string
+		transactionType string
+		want            scannertest.Description
+	}{
+		{
+			name:         "e-banking transfer",
+			description1: "TRSF E-BANKING CR\n1234/FTSCY/WS95031\nJOHN DOE",
+			want: scannertest.Description{
+				Channel:          types.ChannelEBanking,
+				CounterpartyName: "JOHN DOE",
+				Reference:        "1234/FTSCY/WS95031",
+			},
+		},
+		{
+			name:         "transfer line with the bank code",
+			description1: "TRSF E-BANKING DB\n0101/FTFVA/WS95051\n250,000.00\nTRANSFER KE 014 JANE DOE",
+			want: scannertest.Description{
+				Channel:          types.ChannelEBanking,
+				CounterpartyName: "JANE DOE",
+				CounterpartyBank: "014",
+				Reference:        "0101/FTFVA/WS95051",
+			},
+		},
+		{
+			name:         "bank code on the next line",
+			description1: "BI-FAST CR\nBIF TRANSFER DR\n014 JOHN DOE\n1234567890",
+			want: scannertest.Description{
+				Channel:             types.ChannelBIFast,
+				CounterpartyName:    "JOHN DOE",
+				CounterpartyAccount: "1234567890",
+				CounterpartyBank:    "014",
+			},
+		},
+		{
+			name:         "QRIS payment",
+			description1: "TRANSAKSI DEBIT\nQR 008 00000.00ID\nWARUNG MAKMUR",
+			want: scannertest.Description{
+				Channel:          types.ChannelQRIS,
+				CounterpartyName: "WARUNG MAKMUR",
+			},
+		},
+		{
+			name:         "ATM withdrawal",
+			description1: "TARIKAN ATM 01/02\nKCU JAKARTA",
+			want: scannertest.Description{
+				Channel: types.ChannelATM,
+				Remark:  "KCU JAKARTA",
+			},
+		},
+		{
+			name:         "fee",
+			description1: "BIAYA ADM",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trx := &types.Transaction{
+				Description1:    tt.description1,
+				Description2:    tt.description2,
+				TransactionType: tt.transactionType,
+			}
+			readDescription(trx)
+			if got := scannertest.DescriptionOf(trx); got != tt.want {
+				t.Errorf("readDescription() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package bca
+
+import (
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+	"github.com/mrrizkin/omniscan/pkg/pdf"
+	pdftypes "github.com/mrrizkin/omniscan/pkg/pdf/types"
+)
+
+func (*Scanner) Detect(firstPage pdftypes.Rows, metadata *pdf.Metadata) float64 {
+	score := 0.0
+	if types.HasColumns(firstPage, "TANGGAL", "KETERANGAN", "CBG", "MUTASI", "SALDO") {
+		// CBG (cabang) column is only found on BCA statements
+		score += 0.4
+	}
+	if types.HasLabel(firstPage, "NO. REKENING") {
+		score += 0.2
+	}
+	if types.HasLabel(firstPage, "PERIODE") && types.ContainsText(firstPage, months...) {
+		score += 0.1
+	}
+	if types.ContainsText(firstPage, "BCA", "BANK CENTRAL ASIA") {
+		score += 0.2
+	}
+	if types.MetadataContains(metadata, "BCA", "BANK CENTRAL ASIA") {
+		score += 0.1
+	}
+	return score
+}
@@ -1,10 +1,13 @@
 package bca
 
 import (
+	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
 	"github.com/mrrizkin/omniscan/pkg/pdf"
 )
 
@@ -31,12 +34,14 @@ func processPdf(pdfR pdf.PDFReader) (Transactions, Header, error) {
 	transactions := make([]*Transaction, 0)
 	var currentTransaction *Transaction = nil
 	var isNew = false
-	year := "1900"
+	var layout Layout
+	dates := new(types.YearResolver)
 	header := Header{
 		Product:  "",
 		Rekening: "",
 		Periode:  "",
 	}
+	ended := false
 	for pageIndex := 1; pageIndex <= totalPage; pageIndex++ {
 		p, err := pdfR.Page(pageIndex)
 		if err != nil {
@@ -49,33 +54,65 @@ func processPdf(pdfR pdf.PDFReader) (Transactions, Header, error) {
 		}
 		aftTanggal := false
 		shouldStopProcessing := false
+		inSummary := false
+		holderNext := false
 		for _, row := range sortedRows {
+			if inSummary {
+				readSummary(header.Summary, row.Content)
+				continue
+			}
 			if aftTanggal {
+				warn := func(code, message string) {
+					header.Warnings = header.Warnings.AddRow(code, pageIndex, row.Content, message)
+				}
 				isNew, currentTransaction, shouldStopProcessing = IngestRow(
 					currentTransaction,
 					row,
-					year,
+					dates,
+					layout,
+					warn,
 				)
 				if isNew {
 					transactions = append(transactions, currentTransaction)
 				}
+				if currentTransaction != nil && !shouldStopProcessing {
+					currentTransaction.Sources = currentTransaction.Sources.Add(pageIndex, row.Content)
+				}
+				if currentTransaction == nil && !shouldStopProcessing {
+					header.Warnings = header.Warnings.AddRow(
+						types.WarningUnparsedRow,
+						pageIndex,
+						row.Content,
+						"row is before the first transaction",
+					)
+				}
 				if shouldStopProcessing {
-					break
+					// the summary block follows the last transaction
+					inSummary = true
+					ended = true
+					header.Summary = new(types.StatementSummary)
+					readSummary(header.Summary, row.Content)
 				}
 			} else {
+				// the account holder is printed below the branch
+				if holderNext && len(row.Content) > 0 {
+					header.HolderName = row.Content[0].S
+				}
+				holderNext = false
+
 				// here we try to ignore statement end-footer
 				m := 0
 				for wordIndex, word := range row.Content {
-					if year == "1900" {
-						for _, m := range months {
+					if dates.Start.IsZero() {
+						for monthIndex, m := range months {
 							if strings.Contains(word.S, m) {
 								yearStr := strings.TrimPrefix(word.S, m+" ")
 								if yearRegex.MatchString(yearStr) {
-									_, err := strconv.ParseInt(yearStr, 10, 32)
-									if err != nil {
-										year = "1900"
-									} else {
-										year = yearStr
+									year, err := strconv.Atoi(yearStr)
+									if err == nil {
+										// the statement covers the month
+										dates.Start = time.Date(year, time.Month(monthIndex+1), 1, 0, 0, 0, 0, time.UTC)
+										dates.End = dates.Start.AddDate(0, 1, -1)
 									}
 								}
 							}
@@ -99,6 +136,13 @@ func processPdf(pdfR pdf.PDFReader) (Transactions, Header, error) {
 								header.Periode = txt.S
 							}
 						}
+						if strings.Contains(word.S, "MATA UANG") && wordIndex == 0 {
+							header.Currency = types.ValueAfterLabel(row.Content)
+						}
+						if isBranchName(word.S) && wordIndex == 0 {
+							header.Branch = word.S
+							holderNext = true
+						}
 					}
 					if strings.Contains("TANGGAL", word.S) && wordIndex == 0 {
 						m++
@@ -117,10 +161,30 @@ func processPdf(pdfR pdf.PDFReader) (Transactions, Header, error) {
 					}
 					if m == 5 {
 						aftTanggal = true
+						layout = NewLayout(row.Content)
 					}
 				}
 			}
 		}
+		if !aftTanggal && !ended && len(sortedRows) > 0 {
+			header.Warnings = append(header.Warnings, types.Warning{
+				Row:     -1,
+				Code:    types.WarningSkippedRows,
+				Message: fmt.Sprintf("table header not found, %d rows skipped", len(sortedRows)),
+				Page:    pageIndex,
+			})
+		}
 	}
+	header.PeriodStart, header.PeriodEnd = dates.Period()
 	return transactions, header, nil
 }
+
+// BCA prints the branch as KCU (main branch), KCP (sub branch) or KC
+func isBranchName(s string) bool {
+	for _, prefix := range []string{"KCU ", "KCP ", "KC "} {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
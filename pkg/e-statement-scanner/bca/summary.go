@@ -0,0 +1,53 @@
+package bca
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+	"github.com/mrrizkin/omniscan/pkg/money"
+	pdftypes "github.com/mrrizkin/omniscan/pkg/pdf/types"
+)
+
+// readSummary reads a row of the summary printed after the last
+// transaction, the rows look like "MUTASI CR : 500,000.00 3" where the
+// number after the amount is the transaction count
+func readSummary(summary *types.StatementSummary, words pdftypes.TextHorizontal) {
+	texts := make([]string, len(words))
+	for i, word := range words {
+		texts[i] = word.S
+	}
+
+	label, values, found := strings.Cut(strings.Join(texts, " "), ":")
+	if !found {
+		return
+	}
+
+	fields := strings.Fields(values)
+	if len(fields) == 0 {
+		return
+	}
+
+	amount, err := money.Parse(strings.ReplaceAll(fields[0], ",", ""))
+	if err != nil {
+		return
+	}
+
+	count := 0
+	if len(fields) > 1 {
+		count, _ = strconv.Atoi(fields[1])
+	}
+
+	switch strings.TrimSpace(label) {
+	case "SALDO AWAL":
+		summary.OpeningBalance = amount
+	case "MUTASI CR":
+		summary.TotalCredit = amount
+		summary.CreditCount = count
+	case "MUTASI DB":
+		summary.TotalDebit = amount
+		summary.DebitCount = count
+	case "SALDO AKHIR":
+		summary.ClosingBalance = amount
+	}
+}
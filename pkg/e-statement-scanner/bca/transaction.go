@@ -1,51 +1,96 @@
 package bca
 
 import (
-	"strconv"
+	"fmt"
+	"math"
 	"strings"
 	"time"
 
+	estatementtypes "github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+	"github.com/mrrizkin/omniscan/pkg/money"
 	"github.com/mrrizkin/omniscan/pkg/pdf/types"
 )
 
 type Transaction struct {
-	Date         time.Time `json:"date,omitempty"`
-	Description1 string    `json:"description1,omitempty"`
-	Description2 string    `json:"description2,omitempty"`
-	Branch       string    `json:"branch,omitempty"`
-	Change       float64   `json:"change,omitempty"`
-	DirectionCr  *bool     `json:"directionCr,omitempty"`
-	Balance      float64   `json:"balance,omitempty"`
+	Date         time.Time   `json:"date,omitempty"`
+	Description1 string      `json:"description1,omitempty"`
+	Description2 string      `json:"description2,omitempty"`
+	Branch       string      `json:"branch,omitempty"`
+	Change       money.Money `json:"change,omitempty"`
+	DirectionCr  *bool       `json:"directionCr,omitempty"`
+	Balance      money.Money `json:"balance,omitempty"`
+
+	Sources estatementtypes.Sources `json:"sources,omitempty"`
 }
 
 type Transactions []*Transaction
 
 type Header struct {
-	Product  string
-	Rekening string
-	Periode  string
+	Product     string
+	Rekening    string
+	Periode     string
+	PeriodStart *time.Time
+	PeriodEnd   *time.Time
+	HolderName  string
+	Branch      string
+	Currency    string
+	Summary     *estatementtypes.StatementSummary
+	Warnings    estatementtypes.Warnings
+}
+
+// Layout holds the column positions of a page, they are taken from the
+// x position of the table header words so the scanner keeps working when
+// the statement is printed with other margins or paper sizes
+type Layout struct {
+	Date        float64
+	Description float64
+	Branch      float64
+	Change      float64
+	Balance     float64
 }
 
-type leftCol float64
-type rightCol float64
+// words starting within columnTolerance of a header word are aligned to it
+const columnTolerance = 5.0
 
-func (c leftCol) Is(x float64) bool {
-	diff := c - leftCol(x)
-	if diff < 0 {
-		diff = diff * -1
+// NewLayout reads the column positions from the TANGGAL, KETERANGAN, CBG,
+// MUTASI and SALDO header words
+func NewLayout(header types.TextHorizontal) Layout {
+	return Layout{
+		Date:        header[0].X,
+		Description: header[1].X,
+		Branch:      header[2].X,
+		Change:      header[3].X,
+		Balance:     header[4].X,
 	}
-	return diff < 5.0
 }
-func (c rightCol) Is(x float64) bool {
-	return rightCol(x) > c
+
+func (l Layout) isDate(x float64) bool {
+	return x < l.Description-columnTolerance
+}
+
+func (l Layout) isDescription1(x float64) bool {
+	return math.Abs(x-l.Description) < columnTolerance
+}
+
+// the second description column has no header, it is printed under
+// KETERANGAN right of the first one
+func (l Layout) isDescription2(x float64) bool {
+	return x >= l.Description+columnTolerance && x < l.Branch-columnTolerance
+}
+
+func (l Layout) isBranch(x float64) bool {
+	return math.Abs(x-l.Branch) < columnTolerance
 }
 
-const dateCol leftCol = 46.04
-const balanceCol rightCol = 470.0
-const changeAmountCol rightCol = 340.0
-const description1Col leftCol = 92.61
-const description2Col leftCol = 196.71
-const summaryFirstCol leftCol = 180.18
+// amounts are right aligned under MUTASI, the column is assumed to be as
+// wide as the space between the MUTASI and SALDO header words
+func (l Layout) isChange(x float64) bool {
+	return x >= l.Branch+columnTolerance && x > l.Change-(l.Balance-l.Change)/2
+}
+
+func (l Layout) isBalance(x float64) bool {
+	return x > l.Change+(l.Balance-l.Change)/2
+}
 
 // a row with a new date signifies a new transaction
 //
@@ -59,7 +104,9 @@ const summaryFirstCol leftCol = 180.18
 func IngestRow(
 	prevT *Transaction,
 	row *types.Row,
-	year string,
+	dates *estatementtypes.YearResolver,
+	layout Layout,
+	warn estatementtypes.WarnFunc,
 ) (isNew bool, t *Transaction, shouldStopProcessing bool) {
 	words := make(types.TextHorizontal, len(row.Content))
 	copy(words, row.Content)
@@ -68,13 +115,22 @@ func IngestRow(
 			return
 		}
 		t = prevT
-		readSupplementary(t, words)
+		readSupplementary(t, words, layout, warn)
 		return
 
 	}
 	firstWord := words[0]
-	date, dateErr := time.Parse("02/01/2006", firstWord.S+"/"+year)
-	hasDate := dateErr == nil && dateCol.Is(firstWord.X)
+	var date time.Time
+	hasDate := false
+	if layout.isDate(firstWord.X) {
+		// rows only carry the day and month
+		parsed, dateErr := time.Parse("02/01", firstWord.S)
+		if dateErr == nil {
+			date, hasDate = dates.Resolve(parsed), true
+		} else if estatementtypes.LooksLikeDate(firstWord.S) {
+			warn(estatementtypes.WarningUnparsedDate, fmt.Sprintf("date %q can't be read", firstWord.S))
+		}
+	}
 	if !hasDate {
 		if prevT == nil {
 			return
@@ -89,44 +145,56 @@ func IngestRow(
 	}
 
 	lastWord := words[len(words)-1]
-	balance, balanceErr := strconv.ParseFloat(
-		strings.ReplaceAll(lastWord.S, ",", ""),
-		32)
-	hasBalance := balanceErr == nil && balanceCol.Is(lastWord.X)
+	balance, balanceErr := money.Parse(strings.ReplaceAll(lastWord.S, ",", ""))
+	hasBalance := balanceErr == nil && layout.isBalance(lastWord.X)
 	if hasBalance {
 		t.Balance = balance
 		words = words[:len(words)-1]
+	} else if layout.isBalance(lastWord.X) && estatementtypes.LooksLikeAmount(lastWord.S) {
+		warn(estatementtypes.WarningUnparsedAmount, fmt.Sprintf("balance %q can't be read", lastWord.S))
+		words = words[:len(words)-1]
 	}
 
-	shouldStopProcessing = readSupplementary(t, words)
+	shouldStopProcessing = readSupplementary(t, words, layout, warn)
 	return
 }
 
 // readSupplementary try to read words in a row that are apart of
 // date and balance information
-func readSupplementary(t *Transaction, words types.TextHorizontal) (stopProcessingNext bool) {
+//
+// the statement summary starts with SALDO AWAL outside of the description
+// column, the opening balance row has it inside the description column
+func readSupplementary(
+	t *Transaction,
+	words types.TextHorizontal,
+	layout Layout,
+	warn estatementtypes.WarnFunc,
+) (stopProcessingNext bool) {
 	for i, word := range words {
-		if i == 0 && word.S == "SALDO AWAL" && summaryFirstCol.Is(word.X) {
+		if i == 0 && word.S == "SALDO AWAL" && !layout.isDescription1(word.X) {
 			return true
 		}
-		if changeAmountCol.Is(word.X) {
-			amount, amountErr := strconv.ParseFloat(
-				strings.ReplaceAll(word.S, ",", ""),
-				32)
+		if layout.isChange(word.X) {
+			amount, amountErr := money.Parse(strings.ReplaceAll(word.S, ",", ""))
 			if amountErr == nil {
 				isCr := len(words) == i+1 || (words[i+1].S != "DB")
 				t.DirectionCr = &isCr
 				t.Change = amount
+			} else if estatementtypes.LooksLikeAmount(word.S) {
+				warn(estatementtypes.WarningUnparsedAmount, fmt.Sprintf("amount %q can't be read", word.S))
 			}
 		}
-		if description1Col.Is(word.X) {
+		if layout.isBranch(word.X) {
+			t.Branch = word.S
+		}
+		if layout.isDescription1(word.X) {
 			if t.Description1 == "" {
 				t.Description1 = word.S
 			} else {
 				t.Description1 = t.Description1 + "\n" + word.S
 			}
 		}
-		if description2Col.Is(word.X) {
+		if layout.isDescription2(word.X) {
 			if t.Description2 == "" {
 				t.Description2 = word.S
 			} else {
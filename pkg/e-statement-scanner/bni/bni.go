@@ -0,0 +1,112 @@
+package bni
+
+import (
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/mutasi"
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+	"github.com/mrrizkin/omniscan/pkg/pdf"
+)
+
+// statement describes the BNI statement table, BNI prints the table
+// header in english on the e-statement and in indonesian on the
+// statements printed at the branch. The journal number is read into
+// Description2.
+var statement = mutasi.Bank{
+	Headers: []mutasi.Columns{
+		{
+			Date:        "POSTING DATE",
+			Branch:      "BRANCH",
+			Reference:   "JOURNAL",
+			Description: "DESCRIPTION",
+			Amount:      "AMOUNT",
+			Balance:     "BALANCE",
+		},
+		{
+			Date:        "TANGGAL",
+			Branch:      "CABANG",
+			Reference:   "JURNAL",
+			Description: "URAIAN",
+			Amount:      "NOMINAL",
+			Balance:     "SALDO",
+		},
+	},
+	ProductLabels: []string{"Produk", "Product"},
+	ProductTitles: []string{"TAPLUS"},
+	DecimalPoint:  true,
+	DateFormats: []string{
+		"02/01/2006",
+		"02/01/06",
+		"02-01-2006",
+		"02-Jan-2006",
+		"02-Jan-06",
+	},
+	OpeningMarkers: []string{"SALDO AWAL", "OPENING BALANCE", "BEGINNING BALANCE"},
+	ClosingMarkers: []string{"SALDO AKHIR", "ENDING BALANCE", "CLOSING BALANCE"},
+}
+
+type Scanner struct{}
+
+func New() *Scanner {
+	return &Scanner{}
+}
+
+func (*Scanner) ID() string {
+	return "bni"
+}
+
+func (*Scanner) Name() string {
+	return "BNI"
+}
+
+func (*Scanner) Products() []string {
+	return []string{"Taplus", "Taplus Bisnis", "Taplus Muda"}
+}
+
+func (*Scanner) Scan(pdfReader pdf.PDFReader) (*types.ScanResult, error) {
+	trx, header, err := statement.Process(pdfReader)
+	if err != nil {
+		return nil, err
+	}
+
+	return maptrx(header, trx), nil
+}
+
+func ScanFromBytes(filename string, pdfReader pdf.PDFReader) (*types.ScanResult, error) {
+	return New().Scan(pdfReader)
+}
+
+func maptrx(header mutasi.Header, trxs mutasi.Transactions) *types.ScanResult {
+	res := new(types.ScanResult)
+	res.Transactions = make([]*types.Transaction, len(trxs))
+	for i, t := range trxs {
+		trxType := ""
+		if t.DirectionCr != nil {
+			if *t.DirectionCr {
+				trxType = "credit"
+			} else {
+				trxType = "debit"
+			}
+		}
+
+		res.Transactions[i] = &types.Transaction{
+			Date:            t.Date,
+			Description1:    t.Description1,
+			Description2:    t.Description2,
+			Branch:          t.Branch,
+			Change:          t.Change,
+			TransactionType: trxType,
+			Balance:         t.Balance,
+			Sources:         t.Sources,
+		}
+		readDescription(res.Transactions[i])
+	}
+
+	res.Info.Bank = "BNI"
+	res.Info.Produk = header.Product
+	res.Info.Rekening = header.Rekening
+	res.Info.Periode = header.Periode
+	res.Info.Currency = header.Currency
+
+	res.Warnings = header.Warnings
+
+	return res
+}
@@ -0,0 +1,12 @@
+package bni_test
+
+import (
+	"testing"
+
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/bni"
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/scannertest"
+)
+
+func TestScanFixtures(t *testing.T) {
+	scannertest.RunDir(t, bni.New(), "testdata", nil)
+}
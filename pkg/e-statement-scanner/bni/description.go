@@ -0,0 +1,62 @@
+package bni
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+)
+
+var (
+	// e.g. "TRANSFER DARI | JOHN DOE" or "TRANSFER KE | JOHN DOE"
+	counterpartyRegex = regexp.MustCompile(`^TRANSFER (?:DARI|KE) ?\|? ?(.*)$`)
+	// BI-FAST and switching transfers, e.g. "TRANSFER DR 014 JOHN DOE"
+	transferRegex = regexp.MustCompile(`TRANSFER (?:DR|KE) (\d{3}) (.+)$`)
+	accountRegex  = regexp.MustCompile(`^\d{10,16}$`)
+)
+
+// readDescription fills the structured fields of t from the Uraian
+// Transaksi lines, Description2 holds the journal number which is used as
+// the reference
+func readDescription(t *types.Transaction) {
+	t.Reference = strings.TrimSpace(t.Description2)
+
+	lines := types.DescriptionLines(t.Description1)
+	if len(lines) == 0 {
+		return
+	}
+
+	kind := lines[0]
+	switch {
+	case strings.Contains(kind, "BI-FAST") || strings.Contains(kind, "BIFAST"):
+		t.Channel = types.ChannelBIFast
+	case strings.Contains(kind, "QRIS"):
+		t.Channel = types.ChannelQRIS
+	case strings.Contains(kind, "ATM"):
+		t.Channel = types.ChannelATM
+	case strings.Contains(kind, "SWITCHING") || strings.Contains(kind, "ANTAR BANK"):
+		t.Channel = types.ChannelSwitching
+	case strings.Contains(kind, "ECHANNEL") || strings.HasPrefix(kind, "TRANSFER"):
+		t.Channel = types.ChannelEBanking
+	}
+
+	remark := make([]string, 0)
+	for i, line := range lines {
+		switch {
+		case t.CounterpartyName == "" && transferRegex.MatchString(line):
+			match := transferRegex.FindStringSubmatch(line)
+			t.CounterpartyBank, t.CounterpartyName = match[1], match[2]
+		case i == 0 && counterpartyRegex.MatchString(line):
+			t.CounterpartyName = counterpartyRegex.FindStringSubmatch(line)[1]
+		case i == 0:
+			// the kind of transaction stays in Description1
+		case t.CounterpartyAccount == "" && accountRegex.MatchString(line):
+			t.CounterpartyAccount = line
+		case t.CounterpartyName == "" && t.Channel != "" && t.Channel != types.ChannelATM:
+			t.CounterpartyName = strings.TrimSpace(strings.TrimPrefix(line, "|"))
+		default:
+			remark = append(remark, line)
+		}
+	}
+	t.Remark = strings.Join(remark, " ")
+}
@@ -0,0 +1,73 @@
+package bni
+
+import (
+	"testing"
+
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/scannertest"
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+)
+
+func TestReadDescription(t *testing.T) {
+	tests := []struct {
+		name            string
+		description1    string
+		description2    string
+		transactionType string
+		want            scannertest.Description
+	}{
+		{
+			name:         "transfer with the journal",
+			description1: "TRANSFER DARI | JOHN DOE",
+			description2: "123456",
+			want: scannertest.Description{
+				Channel:          types.ChannelEBanking,
+				CounterpartyName: "JOHN DOE",
+				Reference:        "123456",
+			},
+		},
+		{
+			name:         "counterparty on the next line",
+			description1: "TRANSFER KE\n| JANE DOE",
+			want: scannertest.Description{
+				Channel:          types.ChannelEBanking,
+				CounterpartyName: "JANE DOE",
+			},
+		},
+		{
+			name:         "BI-FAST transfer",
+			description1: "BI-FAST CR\nTRANSFER DR 014 JANE DOE\n1234567890",
+			want: scannertest.Description{
+				Channel:             types.ChannelBIFast,
+				CounterpartyName:    "JANE DOE",
+				CounterpartyAccount: "1234567890",
+				CounterpartyBank:    "014",
+			},
+		},
+		{
+			name:         "ATM withdrawal",
+			description1: "TARIK TUNAI ATM\nBNI KCU",
+			want: scannertest.Description{
+				Channel: types.ChannelATM,
+				Remark:  "BNI KCU",
+			},
+		},
+		{
+			name:         "fee",
+			description1: "BIAYA ADM",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trx := &types.Transaction{
+				Description1:    tt.description1,
+				Description2:    tt.description2,
+				TransactionType: tt.transactionType,
+			}
+			readDescription(trx)
+			if got := scannertest.DescriptionOf(trx); got != tt.want {
+				t.Errorf("readDescription() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package bni
+
+import (
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+	"github.com/mrrizkin/omniscan/pkg/pdf"
+	pdftypes "github.com/mrrizkin/omniscan/pkg/pdf/types"
+)
+
+func (*Scanner) Detect(firstPage pdftypes.Rows, metadata *pdf.Metadata) float64 {
+	score := 0.0
+	for _, row := range firstPage {
+		if statement.IsTableHeader(row) {
+			score += 0.4
+			break
+		}
+	}
+	if types.ContainsText(firstPage, "TAPLUS") {
+		score += 0.2
+	}
+	if types.ContainsText(firstPage, "JOURNAL", "JURNAL") {
+		// the journal number column is only found on BNI statements
+		score += 0.1
+	}
+	if types.ContainsText(firstPage, "BNI", "BANK NEGARA INDONESIA") {
+		score += 0.2
+	}
+	if types.MetadataContains(metadata, "BNI", "BANK NEGARA INDONESIA") {
+		score += 0.1
+	}
+	return score
+}
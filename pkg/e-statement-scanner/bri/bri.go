@@ -0,0 +1,100 @@
+package bri
+
+import (
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/mutasi"
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+	"github.com/mrrizkin/omniscan/pkg/pdf"
+)
+
+// statement describes the BRI statement table, the teller column is read
+// into Description2. BRI prints both the debit and the credit column on
+// every row, the column that is not used holds 0.00. There is no opening
+// row, the table is followed by a summary block starting with the opening
+// balance.
+var statement = mutasi.Bank{
+	Headers: []mutasi.Columns{{
+		Date:        "TANGGAL",
+		Description: "URAIAN",
+		Reference:   "TELLER",
+		Debit:       "DEBET",
+		Credit:      "KREDIT",
+		Balance:     "SALDO",
+	}},
+	ProductLabels: []string{"Nama Produk", "Product Name"},
+	DecimalPoint:  true,
+	// BRI prints the year with two digits on the e-statement and four on
+	// the branch printout
+	DateFormats: []string{
+		"02/01/06",
+		"02/01/2006",
+	},
+	ClosingMarkers: []string{"SALDO AWAL"},
+}
+
+type Scanner struct{}
+
+func New() *Scanner {
+	return &Scanner{}
+}
+
+func (*Scanner) ID() string {
+	return "bri"
+}
+
+func (*Scanner) Name() string {
+	return "BRI"
+}
+
+func (*Scanner) Products() []string {
+	return []string{"BritAma", "BritAma Bisnis", "Simpedes"}
+}
+
+func (*Scanner) Scan(pdfReader pdf.PDFReader) (*types.ScanResult, error) {
+	trx, header, err := statement.Process(pdfReader)
+	if err != nil {
+		return nil, err
+	}
+
+	return maptrx(header, trx), nil
+}
+
+func ScanFromBytes(filename string, pdfReader pdf.PDFReader) (*types.ScanResult, error) {
+	return New().Scan(pdfReader)
+}
+
+func maptrx(header mutasi.Header, trxs mutasi.Transactions) *types.ScanResult {
+	res := new(types.ScanResult)
+	res.Transactions = make([]*types.Transaction, len(trxs))
+	for i, t := range trxs {
+		trxType := ""
+		if t.DirectionCr != nil {
+			if *t.DirectionCr {
+				trxType = "credit"
+			} else {
+				trxType = "debit"
+			}
+		}
+
+		res.Transactions[i] = &types.Transaction{
+			Date:            t.Date,
+			Description1:    t.Description1,
+			Description2:    t.Description2,
+			Branch:          t.Branch,
+			Change:          t.Change,
+			TransactionType: trxType,
+			Balance:         t.Balance,
+			Sources:         t.Sources,
+		}
+		readDescription(res.Transactions[i])
+	}
+
+	res.Info.Bank = "BRI"
+	res.Info.Produk = header.Product
+	res.Info.Rekening = header.Rekening
+	res.Info.Periode = header.Periode
+	res.Info.Currency = header.Currency
+
+	res.Warnings = header.Warnings
+
+	return res
+}
@@ -0,0 +1,12 @@
+package bri_test
+
+import (
+	"testing"
+
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/bri"
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/scannertest"
+)
+
+func TestScanFixtures(t *testing.T) {
+	scannertest.RunDir(t, bri.New(), "testdata", nil)
+}
@@ -0,0 +1,65 @@
+package bri
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+)
+
+var (
+	// mobile and internet banking transfers, e.g. "NBMB JOHN DOE TO JANE DOE"
+	mobileRegex = regexp.MustCompile(`^(?:NBMB|IBIB|NBIB) (.+) TO (.+)$`)
+	// BI-FAST and switching transfers, e.g. "BI-FAST CR 014 JOHN DOE"
+	transferRegex = regexp.MustCompile(`^(?:BI-FAST|BIFAST|ATMLTR|SWITCHING) (?:CR|DB|DR|KE)? ?(\d{3}) (.+)$`)
+	accountRegex  = regexp.MustCompile(`^\d{10,16}$`)
+)
+
+// readDescription fills the structured fields of t from the Uraian
+// Transaksi lines, the counterparty of a mobile banking transfer is the
+// sender on credits and the receiver on debits
+func readDescription(t *types.Transaction) {
+	lines := types.DescriptionLines(t.Description1)
+	if len(lines) == 0 {
+		return
+	}
+
+	kind := lines[0]
+	switch {
+	case strings.Contains(kind, "BI-FAST") || strings.Contains(kind, "BIFAST"):
+		t.Channel = types.ChannelBIFast
+	case strings.Contains(kind, "QRIS"):
+		t.Channel = types.ChannelQRIS
+	case strings.HasPrefix(kind, "ATMLTR") || strings.Contains(kind, "SWITCHING"):
+		t.Channel = types.ChannelSwitching
+	case strings.Contains(kind, "ATM"):
+		t.Channel = types.ChannelATM
+	case mobileRegex.MatchString(kind):
+		t.Channel = types.ChannelEBanking
+	}
+
+	remark := make([]string, 0)
+	for i, line := range lines {
+		switch {
+		case t.CounterpartyName == "" && mobileRegex.MatchString(line):
+			match := mobileRegex.FindStringSubmatch(line)
+			t.CounterpartyName = match[2]
+			if t.TransactionType == "credit" {
+				t.CounterpartyName = match[1]
+			}
+		case t.CounterpartyName == "" && transferRegex.MatchString(line):
+			match := transferRegex.FindStringSubmatch(line)
+			t.CounterpartyBank, t.CounterpartyName = match[1], match[2]
+		case i == 0:
+			// the kind of transaction stays in Description1
+		case t.CounterpartyAccount == "" && accountRegex.MatchString(line):
+			t.CounterpartyAccount = line
+		case t.CounterpartyName == "" && t.Channel == types.ChannelQRIS:
+			// QRIS payments print the merchant after the kind
+			t.CounterpartyName = line
+		default:
+			remark = append(remark, line)
+		}
+	}
+	t.Remark = strings.Join(remark, " ")
+}
@@ -0,0 +1,90 @@
+package bri
+
+import (
+	"testing"
+
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/scannertest"
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+)
+
+func TestReadDescription(t *testing.T) {
+	tests := []struct {
+		name            string
+		description1    string
+		description2    string
+		transactionType string
+		want            scannertest.Description
+	}{
+		{
+			name:            "mobile banking credit",
+			description1:    "NBMB JOHN DOE TO JANE DOE",
+			transactionType: "credit",
+			want: scannertest.Description{
+				Channel:          types.ChannelEBanking,
+				CounterpartyName: "JOHN DOE",
+			},
+		},
+		{
+			name:            "mobile banking debit",
+			description1:    "NBMB JOHN DOE TO JANE DOE",
+			transactionType: "debit",
+			want: scannertest.Description{
+				Channel:          types.ChannelEBanking,
+				CounterpartyName: "JANE DOE",
+			},
+		},
+		{
+			name:         "BI-FAST transfer",
+			description1: "BI-FAST CR 014 JOHN DOE\n1234567890",
+			want: scannertest.Description{
+				Channel:             types.ChannelBIFast,
+				CounterpartyName:    "JOHN DOE",
+				CounterpartyAccount: "1234567890",
+				CounterpartyBank:    "014",
+			},
+		},
+		{
+			name:         "switching transfer",
+			description1: "ATMLTR 009 JANE DOE",
+			want: scannertest.Description{
+				Channel:          types.ChannelSwitching,
+				CounterpartyName: "JANE DOE",
+				CounterpartyBank: "009",
+			},
+		},
+		{
+			name:         "QRIS payment",
+			description1: "QRIS PAYMENT\nWARUNG MAKMUR",
+			want: scannertest.Description{
+				Channel:          types.ChannelQRIS,
+				CounterpartyName: "WARUNG MAKMUR",
+			},
+		},
+		{
+			name:         "ATM withdrawal",
+			description1: "TARIK TUNAI ATM\nBRI UNIT",
+			want: scannertest.Description{
+				Channel: types.ChannelATM,
+				Remark:  "BRI UNIT",
+			},
+		},
+		{
+			name:         "fee",
+			description1: "BIAYA ADM",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trx := &types.Transaction{
+				Description1:    tt.description1,
+				Description2:    tt.description2,
+				TransactionType: tt.transactionType,
+			}
+			readDescription(trx)
+			if got := scannertest.DescriptionOf(trx); got != tt.want {
+				t.Errorf("readDescription() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
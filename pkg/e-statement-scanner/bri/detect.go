@@ -0,0 +1,30 @@
+package bri
+
+import (
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+	"github.com/mrrizkin/omniscan/pkg/pdf"
+	pdftypes "github.com/mrrizkin/omniscan/pkg/pdf/types"
+)
+
+func (*Scanner) Detect(firstPage pdftypes.Rows, metadata *pdf.Metadata) float64 {
+	score := 0.0
+	for _, row := range firstPage {
+		if statement.IsTableHeader(row) {
+			score += 0.4
+			break
+		}
+	}
+	if types.ContainsText(firstPage, "BRITAMA", "SIMPEDES") {
+		score += 0.2
+	}
+	if types.HasLabel(firstPage, "No. Rekening") {
+		score += 0.1
+	}
+	if types.ContainsText(firstPage, "BANK RAKYAT INDONESIA", "BANK BRI") {
+		score += 0.2
+	}
+	if types.MetadataContains(metadata, "BANK RAKYAT INDONESIA", "BANK BRI") {
+		score += 0.1
+	}
+	return score
+}
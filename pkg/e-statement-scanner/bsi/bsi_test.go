@@ -0,0 +1,12 @@
+package bsi_test
+
+import (
+	"testing"
+
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/bsi"
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/scannertest"
+)
+
+func TestScanFixtures(t *testing.T) {
+	scannertest.RunDir(t, bsi.New(), "testdata", nil)
+}
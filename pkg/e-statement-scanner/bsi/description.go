@@ -0,0 +1,33 @@
+package bsi
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/mutasi"
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+)
+
+// description reads the Keterangan lines, the first line is the kind of
+// transaction, e.g. "TRANSFER MASUK BSI", the reference is printed as
+// "REF <reference>"
+var description = mutasi.Description{
+	Channel:        channel,
+	ReferenceRegex: regexp.MustCompile(`^REF:? ?(\S+)$`),
+}
+
+func channel(kind string) string {
+	switch {
+	case strings.Contains(kind, "BI-FAST") || strings.Contains(kind, "BIFAST"):
+		return types.ChannelBIFast
+	case strings.Contains(kind, "QRIS"):
+		return types.ChannelQRIS
+	case strings.Contains(kind, "ATM"):
+		return types.ChannelATM
+	case strings.Contains(kind, "ANTAR BANK"):
+		return types.ChannelSwitching
+	case strings.HasPrefix(kind, "TRANSFER"):
+		return types.ChannelEBanking
+	}
+	return ""
+}
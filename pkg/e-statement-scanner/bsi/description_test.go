@@ -0,0 +1,76 @@
+package bsi
+
+import (
+	"testing"
+
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/scannertest"
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+)
+
+func TestReadDescription(t *testing.T) {
+	tests := []struct {
+		name            string
+		description1    string
+		description2    string
+		transactionType string
+		want            scannertest.Description
+	}{
+		{
+			name:         "transfer with a reference line",
+			description1: "TRANSFER MASUK BSI\nDARI AHMAD FAUZI\nREF 9876543210",
+			want: scannertest.Description{
+				Channel:          types.ChannelEBanking,
+				CounterpartyName: "AHMAD FAUZI",
+				Reference:        "9876543210",
+			},
+		},
+		{
+			name:         "BI-FAST transfer",
+			description1: "BI-FAST KELUAR\nKE SITI AMINAH\nBANK BCA 0123456789",
+			want: scannertest.Description{
+				Channel:             types.ChannelBIFast,
+				CounterpartyName:    "SITI AMINAH",
+				CounterpartyAccount: "0123456789",
+				CounterpartyBank:    "BCA",
+			},
+		},
+		{
+			name:         "transfer to another bank",
+			description1: "TRANSFER ANTAR BANK\nKE BUDI\nBANK MANDIRI",
+			want: scannertest.Description{
+				Channel:          types.ChannelSwitching,
+				CounterpartyName: "BUDI",
+				CounterpartyBank: "MANDIRI",
+			},
+		},
+		{
+			name:         "QRIS payment",
+			description1: "QRIS PEMBAYARAN\nTOKO ABC",
+			want: scannertest.Description{
+				Channel:          types.ChannelQRIS,
+				CounterpartyName: "TOKO ABC",
+			},
+		},
+		{
+			name:         "fee",
+			description1: "BIAYA ADMIN\nBULAN JANUARI",
+			want: scannertest.Description{
+				Remark: "BULAN JANUARI",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trx := &types.Transaction{
+				Description1:    tt.description1,
+				Description2:    tt.description2,
+				TransactionType: tt.transactionType,
+			}
+			description.Read(trx)
+			if got := scannertest.DescriptionOf(trx); got != tt.want {
+				t.Errorf("description.Read() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
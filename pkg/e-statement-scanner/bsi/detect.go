@@ -0,0 +1,30 @@
+package bsi
+
+import (
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+	"github.com/mrrizkin/omniscan/pkg/pdf"
+	pdftypes "github.com/mrrizkin/omniscan/pkg/pdf/types"
+)
+
+func (*Scanner) Detect(firstPage pdftypes.Rows, metadata *pdf.Metadata) float64 {
+	score := 0.0
+	for _, row := range firstPage {
+		if statement.IsTableHeader(row) {
+			score += 0.3
+			break
+		}
+	}
+	if types.ContainsText(firstPage, "WADIAH", "MUDHARABAH") {
+		score += 0.2
+	}
+	if types.HasLabel(firstPage, "Nomor Rekening") {
+		score += 0.1
+	}
+	if types.ContainsText(firstPage, "BANK SYARIAH INDONESIA") {
+		score += 0.3
+	}
+	if types.MetadataContains(metadata, "BANK SYARIAH INDONESIA") {
+		score += 0.1
+	}
+	return score
+}
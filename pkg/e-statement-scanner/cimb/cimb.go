@@ -0,0 +1,90 @@
+package cimb
+
+import (
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/mutasi"
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+	"github.com/mrrizkin/omniscan/pkg/pdf"
+)
+
+// statement describes the CIMB Niaga statement table, the value date
+// column is skipped and the Cek/Ref column is read into Description2
+var statement = mutasi.Bank{
+	Headers: []mutasi.Columns{{
+		Date:        "TXN",
+		ValueDate:   "VALUTA",
+		Description: "KETERANGAN",
+		Reference:   "REF",
+		Debit:       "DEBET",
+		Credit:      "KREDIT",
+		Balance:     "SALDO",
+	}},
+	ProductLabels: []string{"Jenis Rekening", "Produk"},
+}
+
+type Scanner struct{}
+
+func New() *Scanner {
+	return &Scanner{}
+}
+
+func (*Scanner) ID() string {
+	return "cimb"
+}
+
+func (*Scanner) Name() string {
+	return "CIMB Niaga"
+}
+
+func (*Scanner) Products() []string {
+	return []string{"Tabungan Xtra", "OCTO Savers", "Tabungan iB Pahala"}
+}
+
+func (*Scanner) Scan(pdfReader pdf.PDFReader) (*types.ScanResult, error) {
+	trx, header, err := statement.Process(pdfReader)
+	if err != nil {
+		return nil, err
+	}
+
+	return maptrx(header, trx), nil
+}
+
+func ScanFromBytes(filename string, pdfReader pdf.PDFReader) (*types.ScanResult, error) {
+	return New().Scan(pdfReader)
+}
+
+func maptrx(header mutasi.Header, trxs mutasi.Transactions) *types.ScanResult {
+	res := new(types.ScanResult)
+	res.Transactions = make([]*types.Transaction, len(trxs))
+	for i, t := range trxs {
+		trxType := ""
+		if t.DirectionCr != nil {
+			if *t.DirectionCr {
+				trxType = "credit"
+			} else {
+				trxType = "debit"
+			}
+		}
+
+		res.Transactions[i] = &types.Transaction{
+			Date:            t.Date,
+			Description1:    t.Description1,
+			Description2:    t.Description2,
+			Branch:          t.Branch,
+			Change:          t.Change,
+			TransactionType: trxType,
+			Balance:         t.Balance,
+			Sources:         t.Sources,
+		}
+		description.Read(res.Transactions[i])
+	}
+
+	res.Info.Bank = "CIMB Niaga"
+	res.Info.Produk = header.Product
+	res.Info.Rekening = header.Rekening
+	res.Info.Periode = header.Periode
+	res.Info.Currency = header.Currency
+
+	res.Warnings = header.Warnings
+
+	return res
+}
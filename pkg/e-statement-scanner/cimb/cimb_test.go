@@ -0,0 +1,12 @@
+package cimb_test
+
+import (
+	"testing"
+
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/cimb"
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/scannertest"
+)
+
+func TestScanFixtures(t *testing.T) {
+	scannertest.RunDir(t, cimb.New(), "testdata", nil)
+}
@@ -0,0 +1,30 @@
+package cimb
+
+import (
+	"strings"
+
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/mutasi"
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+)
+
+// description reads the Keterangan lines, the reference is printed in the
+// Cek/Ref column only
+var description = mutasi.Description{
+	Channel: channel,
+}
+
+func channel(kind string) string {
+	switch {
+	case strings.Contains(kind, "BI-FAST"):
+		return types.ChannelBIFast
+	case strings.Contains(kind, "QRIS"):
+		return types.ChannelQRIS
+	case strings.Contains(kind, "ATM"):
+		return types.ChannelATM
+	case strings.Contains(kind, "SKN") || strings.Contains(kind, "ONLINE"):
+		return types.ChannelSwitching
+	case strings.HasPrefix(kind, "TRF") || strings.HasPrefix(kind, "TRANSFER"):
+		return types.ChannelEBanking
+	}
+	return ""
+}
@@ -0,0 +1,78 @@
+package cimb
+
+import (
+	"testing"
+
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/scannertest"
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+)
+
+func TestReadDescription(t *testing.T) {
+	tests := []struct {
+		name            string
+		description1    string
+		description2    string
+		transactionType string
+		want            scannertest.Description
+	}{
+		{
+			name:         "transfer with a wrapped reference",
+			description1: "TRF KE CIMB\nKE ANDI WIJAYA\n704512345600",
+			description2: "FT2403\n15ABCD",
+			want: scannertest.Description{
+				Channel:             types.ChannelEBanking,
+				CounterpartyName:    "ANDI WIJAYA",
+				CounterpartyAccount: "704512345600",
+				Reference:           "FT240315ABCD",
+			},
+		},
+		{
+			name:         "SKN transfer",
+			description1: "SKN KELUAR\nKE SITI\nBANK BCA 0123456789",
+			want: scannertest.Description{
+				Channel:             types.ChannelSwitching,
+				CounterpartyName:    "SITI",
+				CounterpartyAccount: "0123456789",
+				CounterpartyBank:    "BCA",
+			},
+		},
+		{
+			name:         "reference line is not read",
+			description1: "TRF MASUK\nREF 12345",
+			want: scannertest.Description{
+				Channel: types.ChannelEBanking,
+				Remark:  "REF 12345",
+			},
+		},
+		{
+			name:         "QRIS payment",
+			description1: "QRIS\nTOKO ABC",
+			want: scannertest.Description{
+				Channel:          types.ChannelQRIS,
+				CounterpartyName: "TOKO ABC",
+			},
+		},
+		{
+			name:         "ATM withdrawal",
+			description1: "PENARIKAN ATM\nATM CIMB",
+			want: scannertest.Description{
+				Channel: types.ChannelATM,
+				Remark:  "ATM CIMB",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trx := &types.Transaction{
+				Description1:    tt.description1,
+				Description2:    tt.description2,
+				TransactionType: tt.transactionType,
+			}
+			description.Read(trx)
+			if got := scannertest.DescriptionOf(trx); got != tt.want {
+				t.Errorf("description.Read() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package cimb
+
+import (
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+	"github.com/mrrizkin/omniscan/pkg/pdf"
+	pdftypes "github.com/mrrizkin/omniscan/pkg/pdf/types"
+)
+
+func (*Scanner) Detect(firstPage pdftypes.Rows, metadata *pdf.Metadata) float64 {
+	score := 0.0
+	for _, row := range firstPage {
+		if statement.IsTableHeader(row) {
+			score += 0.4
+			break
+		}
+	}
+	if types.HasLabel(firstPage, "No. Rekening") {
+		score += 0.1
+	}
+	if types.ContainsText(firstPage, "CIMB NIAGA", "OCTO") {
+		score += 0.3
+	}
+	if types.MetadataContains(metadata, "CIMB") {
+		score += 0.2
+	}
+	return score
+}
@@ -0,0 +1,55 @@
+package estatementscanner
+
+import (
+	"errors"
+
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+	"github.com/mrrizkin/omniscan/pkg/pdf"
+)
+
+// below this score the document is considered as not belonging to any
+// registered bank
+const minDetectionScore = 0.3
+
+// detect looks at the first page and the metadata of the document and
+// returns the registered bank scanner that most likely produced it
+func (ms *EStatementScanner) detect(
+	pdfReader pdf.PDFReader,
+	metadata *pdf.Metadata,
+) (types.BankScanner, *types.Detection, error) {
+	if pdfReader.NumPage() < 1 {
+		return nil, nil, errors.New("unable to detect bank: document has no page")
+	}
+
+	page, err := pdfReader.Page(1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	firstPage, err := page.GetTextByRow(2)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var best types.BankScanner
+	detection := &types.Detection{}
+	for _, bank := range ms.Banks() {
+		scanner := ms.banks[bank.ID]
+		score := scanner.Detect(firstPage, metadata)
+		if score > detection.Score {
+			best = scanner
+			detection.Bank = scanner.ID()
+			detection.Score = score
+		}
+	}
+
+	if best == nil || detection.Score < minDetectionScore {
+		return nil, nil, errors.New("unable to detect bank")
+	}
+
+	if detection.Score > 1 {
+		detection.Score = 1
+	}
+
+	return best, detection, nil
+}
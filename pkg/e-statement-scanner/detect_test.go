@@ -0,0 +1,169 @@
+package estatementscanner
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/bca"
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/bni"
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/bri"
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/bsi"
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/cimb"
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/mandiri"
+	"github.com/mrrizkin/omniscan/pkg/pdf/provider/fixture"
+	pdftypes "github.com/mrrizkin/omniscan/pkg/pdf/types"
+)
+
+// the detected bank has to score at least minDetectionMargin above every
+// other bank, a smaller margin means a bank scores text that every bank
+// prints
+const minDetectionMargin = 0.5
+
+// line is a row of a first page, its words are laid out left to right
+type line []string
+
+// firstPages holds the statement header and the table header of every
+// bank, as the banks print them
+var firstPages = map[string][]line{
+	"bca": {
+		{"REKENING TAHAPAN"},
+		{"KCU JAKARTA PUSAT"},
+		{"BUDI SANTOSO"},
+		{"NO. REKENING", ":", "0123456789"},
+		{"PERIODE", ":", "JANUARI 2024"},
+		{"MATA UANG", ":", "IDR"},
+		{"TANGGAL", "KETERANGAN", "CBG", "MUTASI", "SALDO"},
+		{"01/01", "SALDO AWAL", "2,500,000.00"},
+	},
+	"mandiri": {
+		{"REKENING TABUNGAN"},
+		{"Nama", ":", "SITI RAHAYU"},
+		{"Cabang", ":", "KCP JAKARTA SUDIRMAN"},
+		{"Nomor Rekening", "1230004567890"},
+		{"Mata Uang", ":", "IDR"},
+		{"Periode", "15/12/2024", "-", "14/01/2025"},
+		{"TANGGAL", "TRANSAKSI", "DEBIT", "KREDIT", "SALDO"},
+		{"Saldo Awal", "5,000,000.00"},
+		{"PT Bank Mandiri (Persero) Tbk"},
+	},
+	"bni": {
+		{"PT BANK NEGARA INDONESIA (PERSERO) TBK"},
+		{"TAPLUS"},
+		{"Account No", ":", "0123456789"},
+		{"Period", ":", "01/01/2024 - 31/01/2024"},
+		{"Currency", ":", "IDR"},
+		{"Posting Date", "Effective Date", "Branch", "Journal", "Description", "Amount", "Db/Cr", "Balance"},
+		{"SALDO AWAL", "2,500,000.00"},
+	},
+	"bri": {
+		{"PT BANK RAKYAT INDONESIA (PERSERO) TBK"},
+		{"No. Rekening", ":", "0123010012345"},
+		{"Nama Produk", ":", "BritAma"},
+		{"Periode Transaksi", ":", "01/01/2024 - 31/01/2024"},
+		{"Tanggal Transaksi", "Uraian Transaksi", "Teller", "Debet", "Kredit", "Saldo"},
+		{"01/01/24", "08:15:00", "SALDO AWAL", "2,500,000.00"},
+	},
+	"bsi": {
+		{"PT BANK SYARIAH INDONESIA TBK"},
+		{"Nomor Rekening", ":", "7123456789"},
+		{"Nama Produk", ":", "BSI Tabungan Easy Wadiah"},
+		{"Periode", ":", "01/01/2024 - 31/01/2024"},
+		{"Tanggal", "Keterangan", "Debet", "Kredit", "Saldo"},
+		{"SALDO AWAL", "2.500.000,00"},
+	},
+	"cimb": {
+		{"PT BANK CIMB NIAGA TBK"},
+		{"No. Rekening", ":", "704512345600"},
+		{"Jenis Rekening", ":", "Tabungan Xtra"},
+		{"Periode", ":", "01/03/2024 - 31/03/2024"},
+		{"Mata Uang", ":", "IDR"},
+		{"Tgl. Txn", "Tgl. Valuta", "Keterangan", "Cek/Ref", "Debet", "Kredit", "Saldo"},
+		{"SALDO AWAL", "10.000.000,00"},
+	},
+}
+
+func TestDetect(t *testing.T) {
+	ms := withBanks()
+	for bank, lines := range firstPages {
+		t.Run(bank, func(t *testing.T) {
+			reader := pageReader(t, lines)
+
+			scanner, detection, err := ms.detect(reader, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if scanner.ID() != bank {
+				t.Fatalf("detected %s with %.2f, want %s", scanner.ID(), detection.Score, bank)
+			}
+
+			page, _ := reader.Page(1)
+			rows, _ := page.GetTextByRow(2)
+			for _, other := range ms.Banks() {
+				if other.ID == bank {
+					continue
+				}
+				score := ms.banks[other.ID].Detect(rows, nil)
+				if margin := detection.Score - score; margin < minDetectionMargin {
+					t.Errorf("%s scored %.2f, only %.2f below %s", other.ID, score, margin, bank)
+				}
+			}
+		})
+	}
+}
+
+// the labels below are printed by several banks, they alone must not be
+// enough to detect any of them
+func TestDetectSharedLabels(t *testing.T) {
+	ms := withBanks()
+
+	reader := pageReader(t, []line{
+		{"Periode", ":", "01/01/2024 - 31/01/2024"},
+		{"Period", ":", "01/01/2024 - 31/01/2024"},
+		{"Mata Uang", ":", "IDR"},
+		{"Tanggal", "Keterangan", "Jumlah"},
+	})
+	if scanner, detection, err := ms.detect(reader, nil); err == nil {
+		t.Fatalf("detected %s with %.2f", scanner.ID(), detection.Score)
+	}
+}
+
+func withBanks() *EStatementScanner {
+	ms := New()
+	ms.RegisterBank(bca.New())
+	ms.RegisterBank(mandiri.New())
+	ms.RegisterBank(bni.New())
+	ms.RegisterBank(bri.New())
+	ms.RegisterBank(bsi.New())
+	ms.RegisterBank(cimb.New())
+	return ms
+}
+
+// pageReader returns a one page document holding lines, top to bottom
+func pageReader(t *testing.T, lines []line) *fixture.Reader {
+	t.Helper()
+
+	rows := make(pdftypes.Rows, len(lines))
+	for i, l := range lines {
+		y := float64(800 - 12*i)
+		row := &pdftypes.Row{Position: int64(y)}
+		for j, s := range l {
+			row.Content = append(row.Content, pdftypes.Text{
+				FontSize: 8,
+				X:        float64(30 + 70*j),
+				Y:        y,
+				S:        s,
+			})
+		}
+		rows[i] = row
+	}
+
+	b, err := json.Marshal(fixture.Fixture{Pages: []pdftypes.Rows{rows}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := fixture.NewReader("first-page.json", b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return reader
+}
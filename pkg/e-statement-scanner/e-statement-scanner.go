@@ -2,61 +2,111 @@ package estatementscanner
 
 import (
 	"fmt"
+	"strings"
 
-	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/bca"
-	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/mandiri"
 	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
 	"github.com/mrrizkin/omniscan/pkg/pdf"
-	"github.com/mrrizkin/omniscan/pkg/pdf/provider/pdfcpu"
-	"github.com/mrrizkin/omniscan/pkg/pdf/provider/rscpdf"
 )
 
-type EStatementScanner struct{}
+// AutoLibrary tries every registered PDF library and keeps the best result
+const AutoLibrary = "auto"
 
+// DefaultCurrency is used when the statement doesn't print its currency
+const DefaultCurrency = "IDR"
+
+type EStatementScanner struct {
+	banks        map[string]types.BankScanner
+	libraries    []library
+	libraryOrder []string
+}
+
+// New returns an empty scanner, the banks and PDF libraries are registered
+// by the caller with RegisterBank and RegisterLibrary
 func New() *EStatementScanner {
-	return &EStatementScanner{}
+	return &EStatementScanner{
+		banks:     make(map[string]types.BankScanner),
+		libraries: make([]library, 0),
+	}
 }
 
+// Scan reads the e-statement with the given library, when bank is empty
+// the bank is detected from the document itself and when library is empty
+// or "auto" every library is tried.
 func (ms *EStatementScanner) Scan(bank, library, filename string, input []byte) (*types.ScanResult, error) {
-	var pdfReader pdf.PDFReader
-	var err error
-
-	switch library {
-	case "pdfcpu":
-		pdfReader, err = pdfcpu.NewReader(filename, input)
-		if err != nil {
-			return nil, err
-		}
-	case "rscpdf":
-		pdfReader, err = rscpdf.NewReader(filename, input)
-		if err != nil {
-			return nil, err
+	var scanner types.BankScanner
+	if bank != "" {
+		var ok bool
+		scanner, ok = ms.Bank(bank)
+		if !ok {
+			return nil, fmt.Errorf("unsupported bank: %s", bank)
 		}
-	default:
-		return nil, fmt.Errorf("unsupported pdf lib: %s", library)
 	}
 
-	result := &types.ScanResult{}
+	metadata, err := pdf.ExtractMetadata(input, filename)
+	if err != nil {
+		return nil, err
+	}
 
-	switch bank {
-	case "bca":
-		result, err = bca.ScanFromBytes(filename, pdfReader)
-		if err != nil {
-			return nil, err
-		}
-	case "mandiri":
-		result, err = mandiri.ScanFromBytes(filename, pdfReader)
+	var result *types.ScanResult
+	if library == "" || library == AutoLibrary {
+		result, err = ms.scanAuto(scanner, filename, input, metadata)
+	} else {
+		result, err = ms.scan(scanner, library, filename, input, metadata)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result.Metadata = metadata
+
+	return result, nil
+}
+
+func (ms *EStatementScanner) scan(
+	scanner types.BankScanner,
+	library, filename string,
+	input []byte,
+	metadata *pdf.Metadata,
+) (*types.ScanResult, error) {
+	pdfReader, err := ms.open(library, filename, input)
+	if err != nil {
+		return nil, err
+	}
+
+	var detection *types.Detection
+	if scanner == nil {
+		scanner, detection, err = ms.detect(pdfReader, metadata)
 		if err != nil {
 			return nil, err
 		}
-	default:
-		return nil, fmt.Errorf("unsupported bank: %s", bank)
 	}
 
-	result.Metadata, err = pdf.ExtractMetadata(input, filename)
+	result, err := scanner.Scan(pdfReader)
 	if err != nil {
 		return nil, err
 	}
 
+	result.Info.Detection = detection
+	result.Info.Library = library
+	setCurrency(result)
+	Reconcile(result)
+	Review(result)
+
 	return result, nil
 }
+
+// setCurrency normalizes the statement currency and applies it to the
+// transactions that don't have their own
+func setCurrency(result *types.ScanResult) {
+	currency := strings.ToUpper(strings.TrimSpace(result.Info.Currency))
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	result.Info.Currency = currency
+
+	for _, t := range result.Transactions {
+		if t.Currency == "" {
+			t.Currency = currency
+		}
+	}
+}
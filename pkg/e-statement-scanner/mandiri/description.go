@@ -0,0 +1,73 @@
+package mandiri
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+)
+
+var (
+	// e.g. "TRANSFER DARI BUDI SANTOSO" or "TRANSFER KE BANK BCA"
+	transferRegex = regexp.MustCompile(`(?i)^TRANSFER (?:BI[ -]FAST )?(?:DARI|KE) (.+)$`)
+	// e.g. "BANK BCA 0123456789", the account is optional
+	bankRegex      = regexp.MustCompile(`(?i)^BANK (\S+(?: \S+)*?)(?: (\d{6,}))?$`)
+	referenceRegex = regexp.MustCompile(`(?i)^(?:NO\.? )?REF\.?:? ?(\S+)$`)
+	accountRegex   = regexp.MustCompile(`^\d{10,16}$`)
+)
+
+// readDescription fills the structured fields of t from the Keterangan
+// lines, Mandiri prints them in mixed case so they are matched ignoring case
+func readDescription(t *types.Transaction) {
+	lines := types.DescriptionLines(t.Description1, t.Description2)
+	if len(lines) == 0 {
+		return
+	}
+
+	kind := strings.ToUpper(lines[0])
+	switch {
+	case strings.Contains(kind, "BI FAST") || strings.Contains(kind, "BI-FAST"):
+		t.Channel = types.ChannelBIFast
+	case strings.Contains(kind, "QRIS"):
+		t.Channel = types.ChannelQRIS
+	case strings.Contains(kind, "ATM"):
+		t.Channel = types.ChannelATM
+	case strings.HasPrefix(kind, "TRANSFER"):
+		t.Channel = types.ChannelEBanking
+	}
+
+	remark := make([]string, 0)
+	for i, line := range lines {
+		switch {
+		case i == 0 && transferRegex.MatchString(line):
+			// the kind line names the counterparty, or its bank
+			value := transferRegex.FindStringSubmatch(line)[1]
+			if bankRegex.MatchString(value) {
+				readBank(t, value)
+			} else {
+				t.CounterpartyName = value
+			}
+		case i == 0:
+			// the kind of transaction stays in Description1
+		case t.CounterpartyBank == "" && bankRegex.MatchString(line):
+			readBank(t, line)
+		case t.Reference == "" && referenceRegex.MatchString(line):
+			t.Reference = referenceRegex.FindStringSubmatch(line)[1]
+		case t.CounterpartyAccount == "" && accountRegex.MatchString(line):
+			t.CounterpartyAccount = line
+		case t.CounterpartyName == "" && t.Channel != "" && t.Channel != types.ChannelATM:
+			t.CounterpartyName = line
+		default:
+			remark = append(remark, line)
+		}
+	}
+	t.Remark = strings.Join(remark, " ")
+}
+
+func readBank(t *types.Transaction, s string) {
+	match := bankRegex.FindStringSubmatch(s)
+	t.CounterpartyBank = match[1]
+	if match[2] != "" {
+		t.CounterpartyAccount = match[2]
+	}
+}
@@ -0,0 +1,73 @@
+package mandiri
+
+import (
+	"testing"
+
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/scannertest"
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+)
+
+func TestReadDescription(t *testing.T) {
+	tests := []struct {
+		name            string
+		description1    string
+		description2    string
+		transactionType string
+		want            scannertest.Description
+	}{
+		{
+			name:         "transfer naming the counterparty",
+			description1: "Transfer dari BUDI SANTOSO\nNo. Ref: 123456789\n1234567890",
+			want: scannertest.Description{
+				Channel:             types.ChannelEBanking,
+				CounterpartyName:    "BUDI SANTOSO",
+				CounterpartyAccount: "1234567890",
+				Reference:           "123456789",
+			},
+		},
+		{
+			name:         "transfer naming the bank",
+			description1: "Transfer BI Fast ke BANK BCA 0123456789\nJANE DOE",
+			want: scannertest.Description{
+				Channel:             types.ChannelBIFast,
+				CounterpartyName:    "JANE DOE",
+				CounterpartyAccount: "0123456789",
+				CounterpartyBank:    "BCA",
+			},
+		},
+		{
+			name:         "QRIS payment",
+			description1: "Pembayaran QRIS\nTOKO ABC",
+			want: scannertest.Description{
+				Channel:          types.ChannelQRIS,
+				CounterpartyName: "TOKO ABC",
+			},
+		},
+		{
+			name:         "ATM withdrawal",
+			description1: "Tarik Tunai ATM\nATM MANDIRI SUDIRMAN",
+			want: scannertest.Description{
+				Channel: types.ChannelATM,
+				Remark:  "ATM MANDIRI SUDIRMAN",
+			},
+		},
+		{
+			name:         "fee",
+			description1: "Biaya Adm",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trx := &types.Transaction{
+				Description1:    tt.description1,
+				Description2:    tt.description2,
+				TransactionType: tt.transactionType,
+			}
+			readDescription(trx)
+			if got := scannertest.DescriptionOf(trx); got != tt.want {
+				t.Errorf("readDescription() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package mandiri
+
+import (
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+	"github.com/mrrizkin/omniscan/pkg/pdf"
+	pdftypes "github.com/mrrizkin/omniscan/pkg/pdf/types"
+)
+
+func (*Scanner) Detect(firstPage pdftypes.Rows, metadata *pdf.Metadata) float64 {
+	score := 0.0
+	if types.HasColumns(firstPage, "TANGGAL", "TRANSAKSI", "DEBIT", "KREDIT") {
+		score += 0.4
+	}
+	if types.HasLabel(firstPage, "Nomor Rekening") {
+		score += 0.2
+	}
+	if types.HasLabel(firstPage, "Periode") {
+		score += 0.1
+	}
+	if types.ContainsText(firstPage, "MANDIRI") {
+		score += 0.2
+	}
+	if types.MetadataContains(metadata, "MANDIRI") {
+		score += 0.1
+	}
+	return score
+}
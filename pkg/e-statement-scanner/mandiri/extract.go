@@ -1,9 +1,13 @@
 package mandiri
 
 import (
+	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
 	"github.com/mrrizkin/omniscan/pkg/pdf"
 )
 
@@ -30,12 +34,14 @@ func processPdf(pdfR pdf.PDFReader) (Transactions, Header, error) {
 	transactions := make([]*Transaction, 0)
 	var currentTransaction *Transaction = nil
 	var isNew = false
-	year := "1900"
+	var layout Layout
+	dates := new(types.YearResolver)
 	header := Header{
 		Product:  "",
 		Rekening: "",
 		Periode:  "",
 	}
+	ended := false
 	for pageIndex := 1; pageIndex <= totalPage; pageIndex++ {
 		p, err := pdfR.Page(pageIndex)
 		if err != nil {
@@ -48,18 +54,43 @@ func processPdf(pdfR pdf.PDFReader) (Transactions, Header, error) {
 		}
 		aftTanggal := false
 		shouldStopProcessing := false
+		inSummary := false
 		for _, row := range sortedRows {
+			if inSummary {
+				readSummary(header.Summary, row.Content)
+				continue
+			}
 			if aftTanggal {
+				warn := func(code, message string) {
+					header.Warnings = header.Warnings.AddRow(code, pageIndex, row.Content, message)
+				}
 				isNew, currentTransaction, shouldStopProcessing = IngestRow(
 					currentTransaction,
 					row,
-					year,
+					dates,
+					layout,
+					warn,
 				)
 				if isNew {
 					transactions = append(transactions, currentTransaction)
 				}
+				if currentTransaction != nil && !shouldStopProcessing {
+					currentTransaction.Sources = currentTransaction.Sources.Add(pageIndex, row.Content)
+				}
+				if currentTransaction == nil && !shouldStopProcessing {
+					header.Warnings = header.Warnings.AddRow(
+						types.WarningUnparsedRow,
+						pageIndex,
+						row.Content,
+						"row is before the first transaction",
+					)
+				}
 				if shouldStopProcessing {
-					break
+					// the summary block follows the last transaction
+					inSummary = true
+					ended = true
+					header.Summary = new(types.StatementSummary)
+					readSummary(header.Summary, row.Content)
 				}
 			} else {
 				// here we try to ignore statement end-footer
@@ -77,6 +108,17 @@ func processPdf(pdfR pdf.PDFReader) (Transactions, Header, error) {
 								header.Rekening = txt.S
 							}
 						}
+						// "Nama Produk" and "Nama Cabang" share the prefix
+						// of the holder label
+						if isHolderLabel(word.S) && wordIndex == 0 {
+							header.HolderName = types.ValueAfterLabel(row.Content)
+						}
+						if strings.HasPrefix(word.S, "Cabang") && wordIndex == 0 {
+							header.Branch = types.ValueAfterLabel(row.Content)
+						}
+						if strings.HasPrefix(word.S, "Mata Uang") && wordIndex == 0 {
+							header.Currency = types.ValueAfterLabel(row.Content)
+						}
 						if strings.Contains(word.S, "Periode") && wordIndex == 0 {
 							if len(row.Content) > 3 {
 								text := []string{}
@@ -85,7 +127,9 @@ func processPdf(pdfR pdf.PDFReader) (Transactions, Header, error) {
 								}
 								header.Periode = strings.Join(text, " ")
 								toDate := strings.Split(row.Content[3].S, "/")
-								year = toDate[len(toDate)-1]
+								dates.Year, _ = strconv.Atoi(toDate[len(toDate)-1])
+								dates.Start, _ = time.Parse("02/01/2006", row.Content[1].S)
+								dates.End, _ = time.Parse("02/01/2006", row.Content[3].S)
 							}
 						}
 					}
@@ -103,10 +147,44 @@ func processPdf(pdfR pdf.PDFReader) (Transactions, Header, error) {
 					}
 					if m == 4 {
 						aftTanggal = true
+						layout = NewLayout(row.Content)
 					}
 				}
 			}
 		}
+		if !aftTanggal && !ended && len(sortedRows) > 0 {
+			header.Warnings = append(header.Warnings, types.Warning{
+				Row:     -1,
+				Code:    types.WarningSkippedRows,
+				Message: fmt.Sprintf("table header not found, %d rows skipped", len(sortedRows)),
+				Page:    pageIndex,
+			})
+		}
 	}
+	header.PeriodStart, header.PeriodEnd = dates.Period()
+	fillOpeningDate(transactions)
 	return transactions, header, nil
 }
+
+// the opening balance is dated on the period start, when the period is
+// unreadable it takes the date of the first transaction
+func fillOpeningDate(transactions Transactions) {
+	for i, t := range transactions {
+		if !t.Date.IsZero() {
+			continue
+		}
+
+		for _, next := range transactions[i+1:] {
+			if !next.Date.IsZero() {
+				t.Date = next.Date
+				break
+			}
+		}
+	}
+}
+
+// isHolderLabel tells whether s is the account holder label, the ":"
+// separator might be printed in the same word
+func isHolderLabel(s string) bool {
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), ":")) == "Nama"
+}
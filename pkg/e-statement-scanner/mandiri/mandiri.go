@@ -2,10 +2,29 @@ package mandiri
 
 import (
 	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+	"github.com/mrrizkin/omniscan/pkg/money"
 	"github.com/mrrizkin/omniscan/pkg/pdf"
 )
 
-func ScanFromBytes(filename string, pdfReader pdf.PDFReader) (*types.ScanResult, error) {
+type Scanner struct{}
+
+func New() *Scanner {
+	return &Scanner{}
+}
+
+func (*Scanner) ID() string {
+	return "mandiri"
+}
+
+func (*Scanner) Name() string {
+	return "Mandiri"
+}
+
+func (*Scanner) Products() []string {
+	return []string{"Tabungan Mandiri", "Tabungan Bisnis", "Giro"}
+}
+
+func (*Scanner) Scan(pdfReader pdf.PDFReader) (*types.ScanResult, error) {
 	trx, header, err := processPdf(pdfReader)
 	if err != nil {
 		return nil, err
@@ -14,10 +33,14 @@ func ScanFromBytes(filename string, pdfReader pdf.PDFReader) (*types.ScanResult,
 	return maptrx(header, trx), nil
 }
 
+func ScanFromBytes(filename string, pdfReader pdf.PDFReader) (*types.ScanResult, error) {
+	return New().Scan(pdfReader)
+}
+
 func maptrx(header Header, trxs Transactions) *types.ScanResult {
 	res := new(types.ScanResult)
 	res.Transactions = make([]*types.Transaction, len(trxs))
-	totalBalance := 0.0
+	totalBalance := money.Money(0)
 	for i, t := range trxs {
 		totalBalance = totalBalance + t.Balance
 
@@ -38,7 +61,9 @@ func maptrx(header Header, trxs Transactions) *types.ScanResult {
 			Change:          t.Change,
 			TransactionType: trxType,
 			Balance:         t.Balance,
+			Sources:         t.Sources,
 		}
+		readDescription(res.Transactions[i])
 	}
 
 	countTransaction := len(trxs)
@@ -50,6 +75,15 @@ func maptrx(header Header, trxs Transactions) *types.ScanResult {
 	res.Info.Produk = header.Product
 	res.Info.Rekening = header.Rekening
 	res.Info.Periode = header.Periode
+	res.Info.Summary = header.Summary
+	res.Info.PeriodStart = header.PeriodStart
+	res.Info.PeriodEnd = header.PeriodEnd
+	res.Info.HolderName = header.HolderName
+	res.Info.Branch = header.Branch
+	res.Info.Currency = header.Currency
+	res.Info.AccountType = types.AccountType(header.Product)
+
+	res.Warnings = header.Warnings
 
 	return res
 }
@@ -0,0 +1,19 @@
+package mandiri_test
+
+import (
+	"testing"
+
+	estatementscanner "github.com/mrrizkin/omniscan/pkg/e-statement-scanner"
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/mandiri"
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/scannertest"
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+)
+
+func TestScanFixtures(t *testing.T) {
+	scannertest.RunDir(t, mandiri.New(), "testdata", func(t *testing.T, result *types.ScanResult) {
+		estatementscanner.Reconcile(result)
+		if result.Integrity != 1 {
+			t.Errorf("integrity = %v, want 1, issues: %+v", result.Integrity, result.Issues)
+		}
+	})
+}
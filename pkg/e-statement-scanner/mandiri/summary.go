@@ -0,0 +1,55 @@
+package mandiri
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+	"github.com/mrrizkin/omniscan/pkg/money"
+	pdftypes "github.com/mrrizkin/omniscan/pkg/pdf/types"
+)
+
+// readSummary reads a row of the summary printed after the last
+// transaction, the rows look like "Dana Masuk : 500,000.00" and may be
+// followed by the transaction count
+func readSummary(summary *types.StatementSummary, words pdftypes.TextHorizontal) {
+	texts := make([]string, len(words))
+	for i, word := range words {
+		texts[i] = word.S
+	}
+
+	label, values, found := strings.Cut(strings.Join(texts, " "), ":")
+	if !found {
+		return
+	}
+
+	fields := strings.Fields(values)
+	if len(fields) == 0 {
+		return
+	}
+
+	amount, err := money.Parse(strings.ReplaceAll(fields[0], ",", ""))
+	if err != nil {
+		return
+	}
+
+	count := 0
+	if len(fields) > 1 {
+		count, _ = strconv.Atoi(fields[1])
+	}
+
+	// the english label is printed next to the indonesian one
+	label = strings.ToLower(strings.TrimSpace(label))
+	switch {
+	case strings.HasPrefix(label, "saldo awal"):
+		summary.OpeningBalance = amount
+	case strings.HasPrefix(label, "dana masuk"):
+		summary.TotalCredit = amount
+		summary.CreditCount = count
+	case strings.HasPrefix(label, "dana keluar"):
+		summary.TotalDebit = amount
+		summary.DebitCount = count
+	case strings.HasPrefix(label, "saldo akhir"):
+		summary.ClosingBalance = amount
+	}
+}
@@ -1,50 +1,99 @@
 package mandiri
 
 import (
-	"strconv"
+	"fmt"
+	"math"
 	"strings"
 	"time"
 
+	estatementtypes "github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+	"github.com/mrrizkin/omniscan/pkg/money"
 	"github.com/mrrizkin/omniscan/pkg/pdf/types"
 )
 
 type Transaction struct {
-	Date         time.Time `json:"date,omitempty"`
-	Description1 string    `json:"description1,omitempty"`
-	Description2 string    `json:"description2,omitempty"`
-	Branch       string    `json:"branch,omitempty"`
-	Change       float64   `json:"change,omitempty"`
-	DirectionCr  *bool     `json:"directionCr,omitempty"`
-	Balance      float64   `json:"balance,omitempty"`
+	Date         time.Time   `json:"date,omitempty"`
+	Description1 string      `json:"description1,omitempty"`
+	Description2 string      `json:"description2,omitempty"`
+	Branch       string      `json:"branch,omitempty"`
+	Change       money.Money `json:"change,omitempty"`
+	DirectionCr  *bool       `json:"directionCr,omitempty"`
+	Balance      money.Money `json:"balance,omitempty"`
+
+	Sources estatementtypes.Sources `json:"sources,omitempty"`
 }
 
 type Transactions []*Transaction
 
 type Header struct {
-	Product  string
-	Rekening string
-	Periode  string
+	Product     string
+	Rekening    string
+	Periode     string
+	PeriodStart *time.Time
+	PeriodEnd   *time.Time
+	HolderName  string
+	Branch      string
+	Currency    string
+	Summary     *estatementtypes.StatementSummary
+	Warnings    estatementtypes.Warnings
 }
 
-type leftCol float64
-type rightCol float64
+// Layout holds the column positions of a page, they are taken from the
+// x position of the table header words so the scanner keeps working when
+// the statement is printed with other margins or paper sizes
+type Layout struct {
+	Date        float64
+	Description float64
+	Debit       float64
+	Credit      float64
+
+	// Balance is zero on statements printed without the SALDO column
+	Balance float64
+}
 
-func (c leftCol) Is(x float64) bool {
-	diff := c - leftCol(x)
-	if diff < 0 {
-		diff = diff * -1
+// words starting within columnTolerance of a header word are aligned to it
+const columnTolerance = 5.0
+
+// NewLayout reads the column positions from the TANGGAL, TRANSAKSI, DEBIT,
+// KREDIT and SALDO header words
+func NewLayout(header types.TextHorizontal) Layout {
+	layout := Layout{
+		Date:        header[0].X,
+		Description: header[1].X,
+		Debit:       header[2].X,
+		Credit:      header[3].X,
+	}
+	if len(header) > 4 && strings.EqualFold(header[4].S, "SALDO") {
+		layout.Balance = header[4].X
 	}
-	return diff < 5.0
+	return layout
+}
+
+func (l Layout) isDate(x float64) bool {
+	return x < l.Description-columnTolerance
+}
+
+func (l Layout) isDescription(x float64) bool {
+	return math.Abs(x-l.Description) < columnTolerance
 }
-func (c rightCol) Is(x float64) bool {
-	return rightCol(x) > c
+
+// amounts are right aligned under DEBIT and KREDIT, each column is assumed
+// to be as wide as the space between the two header words
+func (l Layout) isDebit(x float64) bool {
+	half := (l.Credit - l.Debit) / 2
+	return x > l.Debit-half && x <= l.Debit+half
 }
 
-const dateCol leftCol = 46.04
-const changeAmountDebitCol rightCol = 400.0
-const changeAmountCreditCol rightCol = 500.0
-const descriptionCol leftCol = 99.61
-const summaryFirstCol leftCol = 99.61
+func (l Layout) isCredit(x float64) bool {
+	if l.Balance != 0 && x > l.Credit+(l.Balance-l.Credit)/2 {
+		return false
+	}
+	return x > l.Debit+(l.Credit-l.Debit)/2
+}
+
+func (l Layout) isBalance(x float64) bool {
+	return l.Balance != 0 && x > l.Credit+(l.Balance-l.Credit)/2
+}
 
 // a row with a new date signifies a new transaction
 //
@@ -58,7 +107,9 @@ const summaryFirstCol leftCol = 99.61
 func IngestRow(
 	prevT *Transaction,
 	row *types.Row,
-	year string,
+	dates *estatementtypes.YearResolver,
+	layout Layout,
+	warn estatementtypes.WarnFunc,
 ) (isNew bool, t *Transaction, shouldStopProcessing bool) {
 	words := make(types.TextHorizontal, len(row.Content))
 	copy(words, row.Content)
@@ -67,61 +118,113 @@ func IngestRow(
 			return
 		}
 		t = prevT
-		shouldStopProcessing = readSupplementary(t, words)
+		shouldStopProcessing = readSupplementary(t, words, layout, warn)
 		return
 	}
 	firstWord := words[0]
-	date, dateErr := time.Parse("02/01/2006", firstWord.S+"/"+year)
-	hasDate := dateErr == nil && dateCol.Is(firstWord.X)
-	if !hasDate {
-		if prevT == nil {
-			return
+	var date time.Time
+	hasDate := false
+	if layout.isDate(firstWord.X) {
+		// rows only carry the day and month
+		parsed, dateErr := time.Parse("02/01", firstWord.S)
+		if dateErr == nil {
+			date, hasDate = dates.Resolve(parsed), true
+		} else if estatementtypes.LooksLikeDate(firstWord.S) {
+			warn(estatementtypes.WarningUnparsedDate, fmt.Sprintf("date %q can't be read", firstWord.S))
 		}
-		t = prevT
-	} else {
+	}
+	switch {
+	case hasDate:
 		isNew = true
 		words = words[1:]
 		t = &Transaction{
 			Date: date,
 		}
+	case isOpeningBalance(words, layout):
+		// the opening balance has no date, it is dated on the period start
+		isNew = true
+		words = words[1:]
+		t = &Transaction{
+			Date:         dates.Start,
+			Description1: "SALDO AWAL",
+		}
+	default:
+		if prevT == nil {
+			return
+		}
+		t = prevT
+	}
+
+	if len(words) > 0 {
+		lastWord := words[len(words)-1]
+		balance, balanceErr := money.Parse(strings.ReplaceAll(lastWord.S, ",", ""))
+		if balanceErr == nil && layout.isBalance(lastWord.X) {
+			t.Balance = balance
+			words = words[:len(words)-1]
+		} else if layout.isBalance(lastWord.X) && estatementtypes.LooksLikeAmount(lastWord.S) {
+			warn(estatementtypes.WarningUnparsedAmount, fmt.Sprintf("balance %q can't be read", lastWord.S))
+			words = words[:len(words)-1]
+		}
 	}
 
-	shouldStopProcessing = readSupplementary(t, words)
+	shouldStopProcessing = readSupplementary(t, words, layout, warn)
 	return
 }
 
+// the opening balance and the summary both start with "Saldo Awal" in the
+// description column, only the summary separates its values with ":"
+func isOpeningBalance(words types.TextHorizontal, layout Layout) bool {
+	return words[0].S == "Saldo Awal" &&
+		layout.isDescription(words[0].X) &&
+		!isSummaryRow(words)
+}
+
+func isSummaryRow(words types.TextHorizontal) bool {
+	for _, word := range words {
+		if strings.HasPrefix(word.S, ":") {
+			return true
+		}
+	}
+	return false
+}
+
 // readSupplementary try to read words in a row that are apart of
 // date and balance information
-func readSupplementary(t *Transaction, words types.TextHorizontal) (stopProcessingNext bool) {
+func readSupplementary(
+	t *Transaction,
+	words types.TextHorizontal,
+	layout Layout,
+	warn estatementtypes.WarnFunc,
+) (stopProcessingNext bool) {
 	for i, word := range words {
-		if i == 0 && word.S == "Saldo Awal" && summaryFirstCol.Is(word.X) {
+		if i == 0 && word.S == "Saldo Awal" && layout.isDescription(word.X) && isSummaryRow(words) {
 			return true
 		}
-		if changeAmountDebitCol.Is(word.X) {
-			amount, amountErr := strconv.ParseFloat(
-				strings.ReplaceAll(word.S, ",", ""),
-				32)
+		if layout.isDebit(word.X) {
+			amount, amountErr := money.Parse(strings.ReplaceAll(word.S, ",", ""))
 			if amountErr == nil {
 				if t.DirectionCr == nil {
 					isCr := false
 					t.DirectionCr = &isCr
 				}
 				t.Change = amount
+			} else if estatementtypes.LooksLikeAmount(word.S) {
+				warn(estatementtypes.WarningUnparsedAmount, fmt.Sprintf("amount %q can't be read", word.S))
 			}
 		}
-		if changeAmountCreditCol.Is(word.X) {
-			amount, amountErr := strconv.ParseFloat(
-				strings.ReplaceAll(word.S, ",", ""),
-				32)
+		if layout.isCredit(word.X) {
+			amount, amountErr := money.Parse(strings.ReplaceAll(word.S, ",", ""))
 			if amountErr == nil {
 				if t.DirectionCr == nil {
 					isCr := true
 					t.DirectionCr = &isCr
 				}
 				t.Change = amount
+			} else if estatementtypes.LooksLikeAmount(word.S) {
+				warn(estatementtypes.WarningUnparsedAmount, fmt.Sprintf("amount %q can't be read", word.S))
 			}
 		}
-		if descriptionCol.Is(word.X) {
+		if layout.isDescription(word.X) {
 			if t.Description1 == "" {
 				t.Description1 = word.S
 			} else {
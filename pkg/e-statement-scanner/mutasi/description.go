@@ -0,0 +1,66 @@
+package mutasi
+
+import (
+	"regexp"
+	"strings"
+
+	estatementtypes "github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+)
+
+var (
+	// e.g. "DARI AHMAD FAUZI" or "KE SITI AMINAH"
+	counterpartyRegex = regexp.MustCompile(`^(?:DARI|KE) (.+)$`)
+	// e.g. "BANK BCA 0123456789", the account is optional
+	bankRegex    = regexp.MustCompile(`^BANK (\S+(?: \S+)*?)(?: (\d{6,}))?$`)
+	accountRegex = regexp.MustCompile(`^\d{10,16}$`)
+)
+
+// Description describes the Keterangan lines of a bank printing the
+// counterparty as "DARI <name>" or "KE <name>" and its bank as
+// "BANK <name> <account>"
+type Description struct {
+	// Channel returns the channel of the kind of transaction, the first
+	// Keterangan line
+	Channel func(kind string) string
+	// ReferenceRegex reads the reference from a Keterangan line, its first
+	// group is the reference. It is left nil when the bank prints the
+	// reference in its own column only.
+	ReferenceRegex *regexp.Regexp
+}
+
+// Read fills the structured fields of t from the Keterangan lines,
+// Description2 holds the reference column which wraps long references
+// over several lines
+func (d Description) Read(t *estatementtypes.Transaction) {
+	t.Reference = strings.Join(estatementtypes.DescriptionLines(t.Description2), "")
+
+	lines := estatementtypes.DescriptionLines(t.Description1)
+	if len(lines) == 0 {
+		return
+	}
+	t.Channel = d.Channel(lines[0])
+
+	remark := make([]string, 0)
+	for _, line := range lines[1:] {
+		switch {
+		case t.CounterpartyName == "" && counterpartyRegex.MatchString(line):
+			t.CounterpartyName = counterpartyRegex.FindStringSubmatch(line)[1]
+		case t.CounterpartyBank == "" && bankRegex.MatchString(line):
+			match := bankRegex.FindStringSubmatch(line)
+			t.CounterpartyBank = match[1]
+			if match[2] != "" {
+				t.CounterpartyAccount = match[2]
+			}
+		case t.Reference == "" && d.ReferenceRegex != nil && d.ReferenceRegex.MatchString(line):
+			t.Reference = d.ReferenceRegex.FindStringSubmatch(line)[1]
+		case t.CounterpartyAccount == "" && accountRegex.MatchString(line):
+			t.CounterpartyAccount = line
+		case t.CounterpartyName == "" && t.Channel == estatementtypes.ChannelQRIS:
+			// QRIS payments print the merchant after the kind
+			t.CounterpartyName = line
+		default:
+			remark = append(remark, line)
+		}
+	}
+	t.Remark = strings.Join(remark, " ")
+}
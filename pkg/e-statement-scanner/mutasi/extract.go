@@ -0,0 +1,201 @@
+package mutasi
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	estatementtypes "github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+	"github.com/mrrizkin/omniscan/pkg/pdf"
+	"github.com/mrrizkin/omniscan/pkg/pdf/types"
+)
+
+// Process reads the header and the transactions of the statement
+func (b Bank) Process(pdfR pdf.PDFReader) (Transactions, Header, error) {
+	totalPage := pdfR.NumPage()
+	transactions := make([]*Transaction, 0)
+	var currentTransaction *Transaction = nil
+	var isNew = false
+	var layout Layout
+	header := Header{
+		Product:  "",
+		Rekening: "",
+		Periode:  "",
+	}
+	ended := false
+	for pageIndex := 1; pageIndex <= totalPage; pageIndex++ {
+		p, err := pdfR.Page(pageIndex)
+		if err != nil {
+			return nil, header, err
+		}
+
+		sortedRows, err := p.GetTextByRow(2)
+		if err != nil {
+			return nil, header, err
+		}
+		aftHeader := false
+		shouldStopProcessing := false
+		for _, row := range sortedRows {
+			if aftHeader {
+				warn := func(code, message string) {
+					header.Warnings = header.Warnings.AddRow(code, pageIndex, row.Content, message)
+				}
+				isNew, currentTransaction, shouldStopProcessing = b.IngestRow(
+					currentTransaction,
+					row,
+					layout,
+					warn,
+				)
+				if isNew {
+					transactions = append(transactions, currentTransaction)
+				}
+				if currentTransaction != nil && !shouldStopProcessing {
+					currentTransaction.Sources = currentTransaction.Sources.Add(pageIndex, row.Content)
+				}
+				if currentTransaction == nil && !shouldStopProcessing {
+					header.Warnings = header.Warnings.AddRow(
+						estatementtypes.WarningUnparsedRow,
+						pageIndex,
+						row.Content,
+						"row is before the first transaction",
+					)
+				}
+				if shouldStopProcessing {
+					ended = true
+					break
+				}
+				continue
+			}
+
+			if pageIndex == 1 {
+				b.readHeader(&header, row)
+			}
+			if b.IsTableHeader(row) {
+				aftHeader = true
+				layout = b.NewLayout(row)
+			}
+		}
+		if !aftHeader && !ended && len(sortedRows) > 0 {
+			header.Warnings = append(header.Warnings, estatementtypes.Warning{
+				Row:     -1,
+				Code:    estatementtypes.WarningSkippedRows,
+				Message: fmt.Sprintf("table header not found, %d rows skipped", len(sortedRows)),
+				Page:    pageIndex,
+			})
+		}
+	}
+
+	b.fillOpeningDate(transactions, header)
+	return transactions, header, nil
+}
+
+func (b Bank) readHeader(header *Header, row *types.Row) {
+	if len(row.Content) == 0 {
+		return
+	}
+
+	label := row.Content[0].S
+	switch {
+	case header.Product == "" && containsAny(strings.ToUpper(label), b.ProductTitles):
+		header.Product = rowText(row)
+	case strings.Contains(label, "Nomor Rekening") ||
+		strings.Contains(label, "No. Rekening") ||
+		strings.Contains(label, "Account No"):
+		if value := estatementtypes.ValueAfterLabel(row.Content); value != "" {
+			header.Rekening = value
+		}
+	case containsAny(label, b.ProductLabels):
+		if value := estatementtypes.ValueAfterLabel(row.Content); value != "" {
+			header.Product = value
+		}
+	case strings.Contains(label, "Periode") || strings.Contains(label, "Period"):
+		if value := estatementtypes.ValueAfterLabel(row.Content); value != "" {
+			header.Periode = value
+		}
+	case strings.HasPrefix(label, "Mata Uang") || strings.HasPrefix(label, "Currency"):
+		if value := estatementtypes.ValueAfterLabel(row.Content); value != "" {
+			header.Currency = value
+		}
+	}
+}
+
+// IsTableHeader tells whether the row holds the header words of one of
+// the table headers of the bank
+func (b Bank) IsTableHeader(row *types.Row) bool {
+	text := strings.ToUpper(rowText(row))
+	for _, columns := range b.Headers {
+		if columns.match(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// match tells whether text holds the header words in order, and the
+// reference column anywhere
+func (c Columns) match(text string) bool {
+	if c.Reference != "" && !strings.Contains(text, c.Reference) {
+		return false
+	}
+	for _, column := range c.names() {
+		index := strings.Index(text, column)
+		if index == -1 {
+			return false
+		}
+		text = text[index+len(column):]
+	}
+	return true
+}
+
+// the opening balance row has no date, it is dated on the first day of
+// the period, or on the first transaction when the period is unreadable
+func (b Bank) fillOpeningDate(transactions Transactions, header Header) {
+	start, ok := b.parseDate(strings.Fields(header.Periode)...)
+	for i, t := range transactions {
+		if !t.Date.IsZero() {
+			continue
+		}
+
+		if ok {
+			t.Date = start
+			continue
+		}
+
+		for _, next := range transactions[i+1:] {
+			if !next.Date.IsZero() {
+				t.Date = next.Date
+				break
+			}
+		}
+	}
+}
+
+func containsAny(s string, labels []string) bool {
+	for _, label := range labels {
+		if strings.Contains(s, label) {
+			return true
+		}
+	}
+	return false
+}
+
+func rowText(row *types.Row) string {
+	text := make([]string, len(row.Content))
+	for i, word := range row.Content {
+		text[i] = word.S
+	}
+	return strings.Join(text, " ")
+}
+
+// parseDate returns the first word that is a date
+func (b Bank) parseDate(words ...string) (time.Time, bool) {
+	for _, word := range words {
+		for _, layout := range b.dateFormats() {
+			date, err := time.Parse(layout, word)
+			if err == nil {
+				return date, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
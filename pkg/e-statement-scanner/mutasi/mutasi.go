@@ -0,0 +1,84 @@
+// Package mutasi reads the account statements printed as a single table
+// of date, description, amount and balance columns, the layout shared by
+// the BSI, CIMB Niaga, BNI and BRI statements. A bank using it only
+// describes its table with a Bank and maps the transactions.
+package mutasi
+
+// Bank describes the statement table of a bank
+type Bank struct {
+	// Headers holds the header words of the table, a bank printing its
+	// table header in more than one language lists every variant
+	Headers []Columns
+	// ProductLabels are the header labels the product is printed after
+	ProductLabels []string
+	// ProductTitles are product names printed as a title row, the whole
+	// row is read as the product, e.g. TAPLUS
+	ProductTitles []string
+	// DecimalPoint tells the amounts are written as 1,250,000.00 instead
+	// of 1.250.000,00
+	DecimalPoint bool
+	// DateFormats are the layouts of the dates, dd/mm/yyyy and dd-mm-yyyy
+	// when empty
+	DateFormats []string
+	// OpeningMarkers label the opening balance row, SALDO AWAL when empty
+	OpeningMarkers []string
+	// ClosingMarkers label the first row after the table, SALDO AKHIR and
+	// TOTAL MUTASI when empty
+	ClosingMarkers []string
+}
+
+// Columns holds the header word of each table column, the columns the
+// bank does not print are left empty. A bank prints either one Amount
+// column, followed by the DB/CR direction, or a Debit and a Credit column.
+type Columns struct {
+	Date        string
+	ValueDate   string
+	Branch      string
+	Reference   string
+	Description string
+	Amount      string
+	Debit       string
+	Credit      string
+	Balance     string
+}
+
+var defaultDateFormats = []string{
+	"02/01/2006",
+	"02-01-2006",
+}
+
+var defaultOpeningMarkers = []string{"SALDO AWAL"}
+var defaultClosingMarkers = []string{"SALDO AKHIR", "TOTAL MUTASI"}
+
+// names returns the header words in reading order, the reference column
+// is left out as the banks print it either before or after the description
+func (c Columns) names() []string {
+	names := make([]string, 0, 9)
+	for _, name := range []string{c.Date, c.ValueDate, c.Branch, c.Description, c.Amount, c.Debit, c.Credit, c.Balance} {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func (b Bank) dateFormats() []string {
+	if len(b.DateFormats) == 0 {
+		return defaultDateFormats
+	}
+	return b.DateFormats
+}
+
+func (b Bank) openingMarkers() []string {
+	if len(b.OpeningMarkers) == 0 {
+		return defaultOpeningMarkers
+	}
+	return b.OpeningMarkers
+}
+
+func (b Bank) closingMarkers() []string {
+	if len(b.ClosingMarkers) == 0 {
+		return defaultClosingMarkers
+	}
+	return b.ClosingMarkers
+}
@@ -0,0 +1,347 @@
+package mutasi
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	estatementtypes "github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+	"github.com/mrrizkin/omniscan/pkg/money"
+	"github.com/mrrizkin/omniscan/pkg/pdf/types"
+)
+
+type Transaction struct {
+	Date         time.Time   `json:"date,omitempty"`
+	Description1 string      `json:"description1,omitempty"`
+	Description2 string      `json:"description2,omitempty"`
+	Branch       string      `json:"branch,omitempty"`
+	Change       money.Money `json:"change,omitempty"`
+	DirectionCr  *bool       `json:"directionCr,omitempty"`
+	Balance      money.Money `json:"balance,omitempty"`
+
+	Sources estatementtypes.Sources `json:"sources,omitempty"`
+}
+
+type Transactions []*Transaction
+
+type Header struct {
+	Product  string
+	Rekening string
+	Periode  string
+	Currency string
+	Warnings estatementtypes.Warnings
+}
+
+// Layout holds the column positions of a page, they are taken from the
+// x position of the table header words
+type Layout struct {
+	Date        float64
+	ValueDate   float64
+	Branch      float64
+	Reference   float64
+	Description float64
+	Amount      float64
+	Debit       float64
+	Credit      float64
+	Balance     float64
+}
+
+// words starting within columnTolerance of a header word are aligned to it
+const columnTolerance = 5.0
+
+// NewLayout reads the column positions from the header words of the
+// table, a column the bank does not print is left at zero
+func (b Bank) NewLayout(row *types.Row) Layout {
+	header := row.Content
+	text := strings.ToUpper(rowText(row))
+	columns := b.Headers[0]
+	for _, c := range b.Headers {
+		if c.match(text) {
+			columns = c
+			break
+		}
+	}
+
+	return Layout{
+		Date:        headerX(header, columns.Date),
+		ValueDate:   headerX(header, columns.ValueDate),
+		Branch:      headerX(header, columns.Branch),
+		Reference:   headerX(header, columns.Reference),
+		Description: headerX(header, columns.Description),
+		Amount:      headerX(header, columns.Amount),
+		Debit:       headerX(header, columns.Debit),
+		Credit:      headerX(header, columns.Credit),
+		Balance:     headerX(header, columns.Balance),
+	}
+}
+
+// headerX returns the x position of the first header word holding name,
+// the first word of a column name is at the left edge of the column
+func headerX(header types.TextHorizontal, name string) float64 {
+	if name == "" {
+		return 0
+	}
+	name = strings.Fields(name)[0]
+	for _, word := range header {
+		if strings.Contains(strings.ToUpper(word.S), name) {
+			return word.X
+		}
+	}
+	return 0
+}
+
+// the dates are printed left of the next column, the header word might
+// not start at the left edge of the column, e.g. "Tgl. Txn"
+func (l Layout) isDate(x float64) bool {
+	if l.ValueDate != 0 {
+		return x < l.ValueDate-columnTolerance
+	}
+	return l.isDateColumns(x)
+}
+
+// isDateColumns tells whether x is left of the first column following
+// the dates, the value date and the posting time are printed there
+func (l Layout) isDateColumns(x float64) bool {
+	if l.Branch != 0 {
+		return x < l.Branch-columnTolerance
+	}
+	return x < l.Description-columnTolerance
+}
+
+func (l Layout) isBranch(x float64) bool {
+	return l.Branch != 0 && math.Abs(x-l.Branch) < columnTolerance
+}
+
+func (l Layout) isReference(x float64) bool {
+	return l.Reference != 0 && math.Abs(x-l.Reference) < columnTolerance
+}
+
+func (l Layout) isDescription(x float64) bool {
+	return math.Abs(x-l.Description) < columnTolerance
+}
+
+// amounts might be aligned to either edge of their column, a word
+// belongs to the amount column whose header word is the closest, the
+// debit column is assumed to be as wide as the credit column
+func (l Layout) isDebit(x float64) bool {
+	return l.Debit != 0 && x > l.Debit-(l.Credit-l.Debit)/2 &&
+		!l.isReference(x) && !l.isCredit(x) && !l.isBalance(x)
+}
+
+func (l Layout) isCredit(x float64) bool {
+	return l.Credit != 0 && x > l.Credit-(l.Credit-l.Debit)/2 && !l.isBalance(x)
+}
+
+// the single amount column is assumed to be as wide as the space between
+// the amount and the balance header words, the DB/CR word follows the
+// amount in the same column
+func (l Layout) isAmount(x float64) bool {
+	return l.Amount != 0 && x > l.Amount-(l.Balance-l.Amount)/2 && !l.isBalance(x)
+}
+
+func (l Layout) isBalance(x float64) bool {
+	last := l.Credit
+	if l.Amount != 0 {
+		last = l.Amount
+	}
+	return x > l.Balance-(l.Balance-last)/2
+}
+
+// a row with a new date signifies a new transaction
+//
+// as a PDF text row might not be a new transaction,
+// but adds detail to the previous transaction, prevT
+// is added as argument to add transaction detail, the
+// banks wrap long descriptions over several rows
+//
+// the opening balance row has no date, it starts with the
+// opening marker and ends with the balance, it is returned
+// as a new transaction holding only the balance
+//
+// the returned isNew tells whether the returned
+// *transaction is a new transaction that should
+// be added to a transaction slice
+func (b Bank) IngestRow(
+	prevT *Transaction,
+	row *types.Row,
+	layout Layout,
+	warn estatementtypes.WarnFunc,
+) (isNew bool, t *Transaction, shouldStopProcessing bool) {
+	words := make(types.TextHorizontal, len(row.Content))
+	copy(words, row.Content)
+	if len(words) == 0 {
+		return
+	}
+
+	firstWord := words[0]
+	lastWord := words[len(words)-1]
+	if hasPrefix(firstWord.S, b.closingMarkers()) {
+		shouldStopProcessing = true
+		return
+	}
+
+	date, hasDate := b.parseDate(strings.Fields(firstWord.S)...)
+	hasDate = hasDate && layout.isDate(firstWord.X)
+	if !hasDate && layout.isDate(firstWord.X) && estatementtypes.LooksLikeDate(firstWord.S) {
+		warn(estatementtypes.WarningUnparsedDate, fmt.Sprintf("date %q can't be read", firstWord.S))
+	}
+	switch {
+	case hasDate:
+		isNew = true
+		words = words[1:]
+		t = &Transaction{
+			Date: date,
+		}
+
+		// the value date and the posting time follow the date, the time
+		// might be printed as its own word past the date column
+		for len(words) > 0 {
+			_, isValueDate := b.parseDate(words[0].S)
+			if !isTime(words[0].S) && !(isValueDate && layout.isDateColumns(words[0].X)) {
+				break
+			}
+			words = words[1:]
+		}
+	case len(words) > 1 && hasPrefix(firstWord.S, b.openingMarkers()) && layout.isBalance(lastWord.X):
+		isNew = true
+		t = &Transaction{
+			Description1: "SALDO AWAL",
+		}
+		if balance, ok := b.parseAmount(lastWord.S); ok {
+			t.Balance = balance
+		} else if estatementtypes.LooksLikeAmount(lastWord.S) {
+			warn(estatementtypes.WarningUnparsedAmount, fmt.Sprintf("balance %q can't be read", lastWord.S))
+		}
+		return
+	default:
+		if prevT == nil {
+			return
+		}
+		t = prevT
+	}
+
+	if len(words) > 0 {
+		lastWord := words[len(words)-1]
+		balance, hasBalance := b.parseAmount(lastWord.S)
+		if hasBalance && layout.isBalance(lastWord.X) {
+			t.Balance = balance
+			words = words[:len(words)-1]
+		} else if layout.isBalance(lastWord.X) && estatementtypes.LooksLikeAmount(lastWord.S) {
+			warn(estatementtypes.WarningUnparsedAmount, fmt.Sprintf("balance %q can't be read", lastWord.S))
+			words = words[:len(words)-1]
+		}
+	}
+
+	b.readSupplementary(t, words, layout, warn)
+	return
+}
+
+// readSupplementary try to read words in a row that are apart of
+// date and balance information, the reference column goes to
+// Description2
+func (b Bank) readSupplementary(
+	t *Transaction,
+	words types.TextHorizontal,
+	layout Layout,
+	warn estatementtypes.WarnFunc,
+) {
+	for _, word := range words {
+		if layout.isAmount(word.X) {
+			if isCr, ok := parseDirection(word.S); ok {
+				t.DirectionCr = &isCr
+				continue
+			}
+
+			amount, ok := b.parseAmount(word.S)
+			if ok {
+				t.Change = amount
+			} else if estatementtypes.LooksLikeAmount(word.S) {
+				warn(estatementtypes.WarningUnparsedAmount, fmt.Sprintf("amount %q can't be read", word.S))
+			}
+			continue
+		}
+		if layout.isCredit(word.X) {
+			amount, ok := b.parseAmount(word.S)
+			if ok && amount != 0 {
+				isCr := true
+				t.DirectionCr = &isCr
+				t.Change = amount
+			} else if !ok && estatementtypes.LooksLikeAmount(word.S) {
+				warn(estatementtypes.WarningUnparsedAmount, fmt.Sprintf("amount %q can't be read", word.S))
+			}
+			continue
+		}
+		if layout.isDebit(word.X) {
+			amount, ok := b.parseAmount(word.S)
+			if ok && amount != 0 {
+				isCr := false
+				t.DirectionCr = &isCr
+				t.Change = amount
+			} else if !ok && estatementtypes.LooksLikeAmount(word.S) {
+				warn(estatementtypes.WarningUnparsedAmount, fmt.Sprintf("amount %q can't be read", word.S))
+			}
+			continue
+		}
+		if layout.isBranch(word.X) {
+			t.Branch = word.S
+		}
+		if layout.isDescription(word.X) {
+			if t.Description1 == "" {
+				t.Description1 = word.S
+			} else {
+				t.Description1 = t.Description1 + "\n" + word.S
+			}
+		}
+		if layout.isReference(word.X) {
+			if t.Description2 == "" {
+				t.Description2 = word.S
+			} else {
+				t.Description2 = t.Description2 + "\n" + word.S
+			}
+		}
+	}
+}
+
+// parseAmount reads amounts written with "." as the thousand separator
+// and "," as the decimal separator, e.g. 1.250.000,00, or the other way
+// around when the bank writes a decimal point
+func (b Bank) parseAmount(s string) (money.Money, bool) {
+	if b.DecimalPoint {
+		s = strings.ReplaceAll(s, ",", "")
+	} else {
+		s = strings.ReplaceAll(s, ".", "")
+		s = strings.ReplaceAll(s, ",", ".")
+	}
+	amount, err := money.Parse(s)
+	return amount, err == nil
+}
+
+// parseDirection reads the DB/CR word of the amount column, D/K is printed
+// on the indonesian statements
+func parseDirection(s string) (isCr bool, ok bool) {
+	switch strings.ToUpper(strings.TrimSpace(s)) {
+	case "D", "DB", "DEBIT":
+		return false, true
+	case "K", "C", "CR", "KREDIT", "CREDIT":
+		return true, true
+	}
+	return false, false
+}
+
+func isTime(s string) bool {
+	_, err := time.Parse("15:04:05", s)
+	return err == nil
+}
+
+// hasPrefix tells whether s starts with one of the markers, the markers
+// are printed in the first column of their row
+func hasPrefix(s string, markers []string) bool {
+	s = strings.ToUpper(s)
+	for _, marker := range markers {
+		if strings.HasPrefix(s, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,209 @@
+package mutasi
+
+import (
+	"testing"
+
+	"github.com/mrrizkin/omniscan/pkg/money"
+	"github.com/mrrizkin/omniscan/pkg/pdf/types"
+)
+
+// the layout of the BNI statement, a single amount column followed by the
+// DB/CR word
+var amountBank = Bank{
+	Headers: []Columns{{
+		Date:        "POSTING DATE",
+		Branch:      "BRANCH",
+		Reference:   "JOURNAL",
+		Description: "DESCRIPTION",
+		Amount:      "AMOUNT",
+		Balance:     "BALANCE",
+	}},
+	DecimalPoint: true,
+}
+
+var amountLayout = Layout{
+	Date:        36,
+	Branch:      124,
+	Reference:   160,
+	Description: 200,
+	Amount:      380,
+	Balance:     500,
+}
+
+func words(words ...types.Text) *types.Row {
+	return &types.Row{Content: words}
+}
+
+func word(x float64, s string) types.Text {
+	return types.Text{X: x, S: s}
+}
+
+func TestIngestRowOpeningBalance(t *testing.T) {
+	tests := []struct {
+		name        string
+		row         *types.Row
+		wantNew     bool
+		wantDesc    string
+		wantBalance money.Money
+	}{
+		{
+			name:        "opening row",
+			row:         words(word(200, "SALDO AWAL"), word(505, "2,500,000.00")),
+			wantNew:     true,
+			wantDesc:    "SALDO AWAL",
+			wantBalance: money.FromInt(2500000),
+		},
+		{
+			name:        "opening row in english",
+			row:         words(word(200, "OPENING BALANCE"), word(505, "2,500,000.00")),
+			wantNew:     true,
+			wantDesc:    "SALDO AWAL",
+			wantBalance: money.FromInt(2500000),
+		},
+		{
+			name:        "description holding the marker",
+			row:         words(word(200, "KOREKSI SALDO AWAL")),
+			wantDesc:    "TRANSFER\nKOREKSI SALDO AWAL",
+			wantBalance: money.FromInt(100),
+		},
+		{
+			name:        "description starting with the marker",
+			row:         words(word(200, "SALDO AWAL TIDAK SESUAI")),
+			wantDesc:    "TRANSFER\nSALDO AWAL TIDAK SESUAI",
+			wantBalance: money.FromInt(100),
+		},
+		{
+			name:        "marker followed by an amount",
+			row:         words(word(200, "SALDO AWAL"), word(384, "1,000.00")),
+			wantDesc:    "TRANSFER\nSALDO AWAL",
+			wantBalance: money.FromInt(100),
+		},
+	}
+
+	bank := amountBank
+	bank.OpeningMarkers = []string{"SALDO AWAL", "OPENING BALANCE"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prev := &Transaction{Description1: "TRANSFER", Balance: money.FromInt(100)}
+			warn := func(code, message string) {
+				t.Errorf("unexpected warning %s: %s", code, message)
+			}
+
+			isNew, got, stop := bank.IngestRow(prev, tt.row, amountLayout, warn)
+			if stop {
+				t.Fatal("IngestRow() stopped processing")
+			}
+			if isNew != tt.wantNew {
+				t.Fatalf("IngestRow() isNew = %v, want %v", isNew, tt.wantNew)
+			}
+			if !tt.wantNew && got != prev {
+				t.Fatal("IngestRow() didn't return the previous transaction")
+			}
+			if got.Description1 != tt.wantDesc {
+				t.Errorf("Description1 = %q, want %q", got.Description1, tt.wantDesc)
+			}
+			if got.Balance != tt.wantBalance {
+				t.Errorf("Balance = %v, want %v", got.Balance, tt.wantBalance)
+			}
+		})
+	}
+}
+
+func TestLayoutAmountColumn(t *testing.T) {
+	tests := []struct {
+		name        string
+		x           float64
+		wantAmount  bool
+		wantBalance bool
+	}{
+		{name: "description", x: 200},
+		{name: "left of the amount header", x: 330, wantAmount: true},
+		{name: "amount header", x: 384, wantAmount: true},
+		{name: "direction", x: 432, wantAmount: true},
+		{name: "between amount and balance", x: 441, wantBalance: true},
+		{name: "balance header", x: 505, wantBalance: true},
+		{name: "right of the balance header", x: 560, wantBalance: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := amountLayout.isAmount(tt.x); got != tt.wantAmount {
+				t.Errorf("isAmount(%v) = %v, want %v", tt.x, got, tt.wantAmount)
+			}
+			if got := amountLayout.isBalance(tt.x); got != tt.wantBalance {
+				t.Errorf("isBalance(%v) = %v, want %v", tt.x, got, tt.wantBalance)
+			}
+		})
+	}
+}
+
+// the balance printed right of the amount column is not read as the
+// amount of the transaction
+func TestIngestRowAmountAndBalance(t *testing.T) {
+	row := words(
+		word(36, "02/01/2024"),
+		word(80, "02/01/2024"),
+		word(124, "0259"),
+		word(160, "123456"),
+		word(200, "TRANSFER"),
+		word(384, "1,250,000.00"),
+		word(432, "K"),
+		word(505, "3,750,000.00"),
+	)
+	warn := func(code, message string) {
+		t.Errorf("unexpected warning %s: %s", code, message)
+	}
+
+	isNew, got, _ := amountBank.IngestRow(nil, row, amountLayout, warn)
+	if !isNew {
+		t.Fatal("IngestRow() isNew = false, want true")
+	}
+	if want := money.FromInt(1250000); got.Change != want {
+		t.Errorf("Change = %v, want %v", got.Change, want)
+	}
+	if want := money.FromInt(3750000); got.Balance != want {
+		t.Errorf("Balance = %v, want %v", got.Balance, want)
+	}
+	if got.DirectionCr == nil || !*got.DirectionCr {
+		t.Errorf("DirectionCr = %v, want credit", got.DirectionCr)
+	}
+	if got.Branch != "0259" || got.Description2 != "123456" || got.Description1 != "TRANSFER" {
+		t.Errorf("Branch, Description2, Description1 = %q, %q, %q", got.Branch, got.Description2, got.Description1)
+	}
+}
+
+func TestIngestRowClosingMarker(t *testing.T) {
+	tests := []struct {
+		name     string
+		row      *types.Row
+		wantStop bool
+	}{
+		{
+			name:     "closing row",
+			row:      words(word(200, "SALDO AKHIR"), word(505, "2,500,000.00")),
+			wantStop: true,
+		},
+		{
+			name:     "summary label in the date column",
+			row:      words(word(36, "TOTAL MUTASI"), word(200, "2")),
+			wantStop: true,
+		},
+		{
+			name: "description holding the marker",
+			row:  words(word(200, "INFO SALDO AKHIR BULAN")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prev := &Transaction{Description1: "TRANSFER"}
+			warn := func(code, message string) {
+				t.Errorf("unexpected warning %s: %s", code, message)
+			}
+
+			if _, _, stop := amountBank.IngestRow(prev, tt.row, amountLayout, warn); stop != tt.wantStop {
+				t.Errorf("IngestRow() shouldStopProcessing = %v, want %v", stop, tt.wantStop)
+			}
+		})
+	}
+}
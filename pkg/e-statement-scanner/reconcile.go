@@ -0,0 +1,199 @@
+package estatementscanner
+
+import (
+	"fmt"
+
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+	"github.com/mrrizkin/omniscan/pkg/money"
+)
+
+// Reconcile walks the running balance of the transactions and records an
+// issue for every row where the last printed balance plus or minus the
+// changes since doesn't match the printed balance, the integrity score is
+// the fraction of checked rows that reconcile.
+//
+// Some banks only print the balance on the last row of a day, e.g. BCA,
+// a row without a balance is carried forward to the next printed one. The
+// opening balance row, repeated on every page by some banks, restarts the
+// running balance.
+func Reconcile(result *types.ScanResult) {
+	issues := make([]types.Issue, 0)
+	checked, reconciled := 0, 0
+
+	var balance, changes money.Money
+	hasBalance := false
+	for i, t := range result.Transactions {
+		if isOpeningBalance(t) {
+			balance, changes, hasBalance = t.Balance, 0, true
+			continue
+		}
+		if t.TransactionType == "" {
+			issues = append(issues, types.Issue{
+				Row:     i,
+				Code:    types.IssueUnknownDirection,
+				Message: "transaction has no debit or credit direction",
+			})
+			// the change can't be added up, the running balance restarts
+			// at the next printed balance
+			hasBalance = false
+			continue
+		}
+
+		changes += signedChange(t)
+		if !printsBalance(t) {
+			continue
+		}
+		if !hasBalance {
+			balance, changes, hasBalance = t.Balance, 0, true
+			continue
+		}
+
+		expected := balance + changes
+		checked++
+		if expected == t.Balance {
+			reconciled++
+		} else {
+			issues = append(issues, types.Issue{
+				Row:  i,
+				Code: types.IssueBalanceMismatch,
+				Message: fmt.Sprintf(
+					"balance %s doesn't match last printed balance %s with a change of %s",
+					t.Balance,
+					balance,
+					changes,
+				),
+				Expected: expected,
+				Actual:   t.Balance,
+			})
+		}
+		balance, changes = t.Balance, 0
+	}
+
+	if result.Info.Summary != nil {
+		issues = append(issues, checkSummary(result.Info.Summary, result.Transactions)...)
+	}
+
+	result.Issues = issues
+	result.Integrity = 0
+	if checked > 0 {
+		result.Integrity = float64(reconciled) / float64(checked)
+	}
+}
+
+// isOpeningBalance tells whether t is the opening balance row the scanners
+// read before the first transaction
+func isOpeningBalance(t *types.Transaction) bool {
+	return t.TransactionType == "" && t.Change == 0 && t.Description1 == "SALDO AWAL"
+}
+
+// printsBalance tells whether the balance is printed on the row, a row
+// without one is read with a zero balance
+func printsBalance(t *types.Transaction) bool {
+	return t.Balance != 0
+}
+
+// signedChange returns the change of t, negative for debits
+func signedChange(t *types.Transaction) money.Money {
+	switch t.TransactionType {
+	case "credit":
+		return t.Change
+	case "debit":
+		return -t.Change
+	}
+	return 0
+}
+
+// checkSummary compares the totals declared in the statement summary with
+// the ones computed from the transactions
+func checkSummary(summary *types.StatementSummary, transactions []*types.Transaction) []types.Issue {
+	issues := make([]types.Issue, 0)
+	if len(transactions) == 0 {
+		return issues
+	}
+
+	var computed types.StatementSummary
+	for _, t := range transactions {
+		switch t.TransactionType {
+		case "credit":
+			computed.TotalCredit += t.Change
+			computed.CreditCount++
+		case "debit":
+			computed.TotalDebit += t.Change
+			computed.DebitCount++
+		}
+	}
+
+	// the opening balance is the first printed balance without the changes
+	// up to it, the closing balance the last one with the changes after it
+	var changes money.Money
+	for _, t := range transactions {
+		changes += signedChange(t)
+		if printsBalance(t) || isOpeningBalance(t) {
+			computed.OpeningBalance = t.Balance - changes
+			break
+		}
+	}
+	changes = 0
+	for i := len(transactions) - 1; i >= 0; i-- {
+		t := transactions[i]
+		if printsBalance(t) || isOpeningBalance(t) {
+			computed.ClosingBalance = t.Balance + changes
+			break
+		}
+		changes += signedChange(t)
+	}
+
+	amounts := []struct {
+		name               string
+		declared, computed money.Money
+	}{
+		{"opening balance", summary.OpeningBalance, computed.OpeningBalance},
+		{"total credit", summary.TotalCredit, computed.TotalCredit},
+		{"total debit", summary.TotalDebit, computed.TotalDebit},
+		{"closing balance", summary.ClosingBalance, computed.ClosingBalance},
+	}
+	for _, amount := range amounts {
+		if amount.declared == amount.computed {
+			continue
+		}
+
+		issues = append(issues, types.Issue{
+			Row:  -1,
+			Code: types.IssueSummaryMismatch,
+			Message: fmt.Sprintf(
+				"declared %s %s doesn't match %s computed from the transactions",
+				amount.name,
+				amount.declared,
+				amount.computed,
+			),
+			Expected: amount.declared,
+			Actual:   amount.computed,
+		})
+	}
+
+	counts := []struct {
+		name               string
+		declared, computed int
+	}{
+		{"credit count", summary.CreditCount, computed.CreditCount},
+		{"debit count", summary.DebitCount, computed.DebitCount},
+	}
+	for _, count := range counts {
+		if count.declared == 0 || count.declared == count.computed {
+			continue
+		}
+
+		issues = append(issues, types.Issue{
+			Row:  -1,
+			Code: types.IssueSummaryMismatch,
+			Message: fmt.Sprintf(
+				"declared %s %d doesn't match %d transactions",
+				count.name,
+				count.declared,
+				count.computed,
+			),
+		})
+	}
+
+	return issues
+}
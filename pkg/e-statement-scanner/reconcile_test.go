@@ -0,0 +1,219 @@
+package estatementscanner
+
+import (
+	"testing"
+
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+	"github.com/mrrizkin/omniscan/pkg/money"
+)
+
+func opening(balance int64) *types.Transaction {
+	return &types.Transaction{Description1: "SALDO AWAL", Balance: money.FromInt(balance)}
+}
+
+func credit(change, balance int64) *types.Transaction {
+	return &types.Transaction{TransactionType: "credit", Change: money.FromInt(change), Balance: money.FromInt(balance)}
+}
+
+func debit(change, balance int64) *types.Transaction {
+	return &types.Transaction{TransactionType: "debit", Change: money.FromInt(change), Balance: money.FromInt(balance)}
+}
+
+func TestReconcile(t *testing.T) {
+	tests := []struct {
+		name          string
+		transactions  []*types.Transaction
+		wantIntegrity float64
+		wantIssues    []types.Issue
+	}{
+		{
+			name: "every row prints its balance",
+			transactions: []*types.Transaction{
+				opening(1000),
+				credit(500, 1500),
+				debit(200, 1300),
+			},
+			wantIntegrity: 1,
+		},
+		{
+			name: "first row without an opening row",
+			transactions: []*types.Transaction{
+				credit(500, 1500),
+				debit(200, 1300),
+			},
+			wantIntegrity: 1,
+		},
+		{
+			// BCA prints the balance on the last row of a day only
+			name: "balance carried to the last row of the day",
+			transactions: []*types.Transaction{
+				opening(1000),
+				credit(500, 0),
+				debit(200, 0),
+				credit(100, 1400),
+				debit(400, 1000),
+			},
+			wantIntegrity: 1,
+		},
+		{
+			name: "mismatch after carried rows",
+			transactions: []*types.Transaction{
+				opening(1000),
+				credit(500, 0),
+				debit(200, 1200),
+				debit(100, 1100),
+			},
+			wantIntegrity: 0.5,
+			wantIssues: []types.Issue{
+				{Row: 2, Code: types.IssueBalanceMismatch, Expected: money.FromInt(1300), Actual: money.FromInt(1200)},
+			},
+		},
+		{
+			name: "mismatch restarts at the printed balance",
+			transactions: []*types.Transaction{
+				opening(1000),
+				credit(500, 1600),
+				debit(100, 1500),
+			},
+			wantIntegrity: 0.5,
+			wantIssues: []types.Issue{
+				{Row: 1, Code: types.IssueBalanceMismatch, Expected: money.FromInt(1500), Actual: money.FromInt(1600)},
+			},
+		},
+		{
+			name: "opening row repeated on every page",
+			transactions: []*types.Transaction{
+				opening(1000),
+				credit(500, 1500),
+				opening(1500),
+				debit(200, 1300),
+			},
+			wantIntegrity: 1,
+		},
+		{
+			name: "unknown direction",
+			transactions: []*types.Transaction{
+				opening(1000),
+				{Description1: "KOREKSI", Change: money.FromInt(50), Balance: money.FromInt(1050)},
+				credit(100, 1150),
+				debit(150, 1000),
+			},
+			wantIntegrity: 1,
+			wantIssues: []types.Issue{
+				{Row: 1, Code: types.IssueUnknownDirection},
+			},
+		},
+		{
+			name:          "nothing to check",
+			transactions:  []*types.Transaction{opening(1000)},
+			wantIntegrity: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &types.ScanResult{Transactions: tt.transactions}
+			Reconcile(result)
+
+			if result.Integrity != tt.wantIntegrity {
+				t.Errorf("Integrity = %v, want %v", result.Integrity, tt.wantIntegrity)
+			}
+			assertIssues(t, result.Issues, tt.wantIssues)
+		})
+	}
+}
+
+func TestCheckSummary(t *testing.T) {
+	transactions := []*types.Transaction{
+		opening(1000),
+		credit(500, 0),
+		debit(200, 1300),
+		credit(100, 1400),
+		debit(50, 0),
+	}
+
+	tests := []struct {
+		name         string
+		summary      types.StatementSummary
+		transactions []*types.Transaction
+		wantIssues   []types.Issue
+	}{
+		{
+			name: "matching summary",
+			summary: types.StatementSummary{
+				OpeningBalance: money.FromInt(1000),
+				TotalCredit:    money.FromInt(600),
+				CreditCount:    2,
+				TotalDebit:     money.FromInt(250),
+				DebitCount:     2,
+				ClosingBalance: money.FromInt(1350),
+			},
+			transactions: transactions,
+		},
+		{
+			name: "counts not printed",
+			summary: types.StatementSummary{
+				OpeningBalance: money.FromInt(1000),
+				TotalCredit:    money.FromInt(600),
+				TotalDebit:     money.FromInt(250),
+				ClosingBalance: money.FromInt(1350),
+			},
+			transactions: transactions,
+		},
+		{
+			name: "opening balance without an opening row",
+			summary: types.StatementSummary{
+				OpeningBalance: money.FromInt(1000),
+				TotalCredit:    money.FromInt(600),
+				TotalDebit:     money.FromInt(200),
+				ClosingBalance: money.FromInt(1400),
+			},
+			transactions: transactions[1:4],
+		},
+		{
+			name: "mismatching totals and counts",
+			summary: types.StatementSummary{
+				OpeningBalance: money.FromInt(1000),
+				TotalCredit:    money.FromInt(700),
+				CreditCount:    3,
+				TotalDebit:     money.FromInt(250),
+				DebitCount:     2,
+				ClosingBalance: money.FromInt(1450),
+			},
+			transactions: transactions,
+			wantIssues: []types.Issue{
+				{Row: -1, Code: types.IssueSummaryMismatch, Expected: money.FromInt(700), Actual: money.FromInt(600)},
+				{Row: -1, Code: types.IssueSummaryMismatch, Expected: money.FromInt(1450), Actual: money.FromInt(1350)},
+				{Row: -1, Code: types.IssueSummaryMismatch},
+			},
+		},
+		{
+			name:    "no transactions",
+			summary: types.StatementSummary{OpeningBalance: money.FromInt(1000)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := tt.summary
+			assertIssues(t, checkSummary(&summary, tt.transactions), tt.wantIssues)
+		})
+	}
+}
+
+// assertIssues compares the row, code and amounts of the issues, the
+// messages are left out
+func assertIssues(t *testing.T, got, want []types.Issue) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d issues, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		got := got[i]
+		got.Message = ""
+		if got != want[i] {
+			t.Errorf("issue %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+}
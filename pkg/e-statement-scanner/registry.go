@@ -0,0 +1,81 @@
+package estatementscanner
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+	"github.com/mrrizkin/omniscan/pkg/pdf"
+)
+
+type library struct {
+	id   string
+	open pdf.OpenFunc
+}
+
+// RegisterBank adds a bank scanner to the registry, registering the same
+// identifier twice replaces the previous scanner.
+func (ms *EStatementScanner) RegisterBank(scanner types.BankScanner) {
+	ms.banks[scanner.ID()] = scanner
+}
+
+// RegisterLibrary adds a PDF provider that can be picked with the
+// pdf_library field.
+func (ms *EStatementScanner) RegisterLibrary(id string, open pdf.OpenFunc) {
+	for i, lib := range ms.libraries {
+		if lib.id == id {
+			ms.libraries[i].open = open
+			return
+		}
+	}
+
+	ms.libraries = append(ms.libraries, library{id: id, open: open})
+}
+
+func (ms *EStatementScanner) Bank(id string) (types.BankScanner, bool) {
+	scanner, ok := ms.banks[id]
+	return scanner, ok
+}
+
+func (ms *EStatementScanner) Banks() []types.BankInfo {
+	banks := make([]types.BankInfo, 0, len(ms.banks))
+	for _, scanner := range ms.banks {
+		banks = append(banks, types.BankInfo{
+			ID:       scanner.ID(),
+			Name:     scanner.Name(),
+			Products: scanner.Products(),
+		})
+	}
+
+	sort.Slice(banks, func(i, j int) bool {
+		return banks[i].ID < banks[j].ID
+	})
+	return banks
+}
+
+func (ms *EStatementScanner) Libraries() []string {
+	libraries := make([]string, len(ms.libraries))
+	for i, lib := range ms.libraries {
+		libraries[i] = lib.id
+	}
+	return libraries
+}
+
+func (ms *EStatementScanner) hasLibrary(id string) bool {
+	for _, lib := range ms.libraries {
+		if lib.id == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (ms *EStatementScanner) open(id, filename string, input []byte) (pdf.PDFReader, error) {
+	for _, lib := range ms.libraries {
+		if lib.id == id {
+			return lib.open(filename, input)
+		}
+	}
+
+	return nil, fmt.Errorf("unsupported pdf lib: %s", id)
+}
@@ -0,0 +1,105 @@
+package estatementscanner
+
+import (
+	"fmt"
+
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+)
+
+// confidence left after an issue or a warning, a transaction with several
+// of them multiplies their factors
+var confidenceFactors = map[string]float64{
+	types.IssueBalanceMismatch:    0.5,
+	types.IssueUnknownDirection:   0.7,
+	types.IssueSummaryMismatch:    0.8,
+	types.WarningSkippedRows:      0.9,
+	types.WarningUnparsedRow:      0.9,
+	types.WarningMissingAmount:    0.5,
+	types.WarningMissingDirection: 0.7,
+	types.WarningMissingDate:      0.5,
+	types.WarningDateOutOfPeriod:  0.8,
+	types.WarningUnparsedAmount:   0.5,
+	types.WarningUnparsedDate:     0.7,
+}
+
+// Review records a warning for every transaction that looks wrong on its
+// own, then scores the result. It must run after Reconcile.
+func Review(result *types.ScanResult) {
+	start, end := result.Info.PeriodStart, result.Info.PeriodEnd
+	for i, t := range result.Transactions {
+		if t.Change == 0 && i > 0 {
+			result.Warnings = append(result.Warnings, types.Warning{
+				Row:     i,
+				Code:    types.WarningMissingAmount,
+				Message: "transaction has no amount",
+			})
+		}
+
+		if t.Change != 0 && t.TransactionType == "" {
+			result.Warnings = append(result.Warnings, types.Warning{
+				Row:     i,
+				Code:    types.WarningMissingDirection,
+				Message: fmt.Sprintf("amount %s is neither debit nor credit", t.Change),
+			})
+		}
+
+		switch {
+		case t.Date.IsZero():
+			result.Warnings = append(result.Warnings, types.Warning{
+				Row:     i,
+				Code:    types.WarningMissingDate,
+				Message: "transaction has no date",
+			})
+		case start != nil && end != nil && (t.Date.Before(*start) || !t.Date.Before(end.AddDate(0, 0, 1))):
+			result.Warnings = append(result.Warnings, types.Warning{
+				Row:  i,
+				Code: types.WarningDateOutOfPeriod,
+				Message: fmt.Sprintf(
+					"date %s is outside the period %s - %s",
+					t.Date.Format("2006-01-02"),
+					start.Format("2006-01-02"),
+					end.Format("2006-01-02"),
+				),
+			})
+		}
+	}
+
+	Score(result)
+}
+
+// Score sets the confidence of every transaction from the issues and
+// warnings about it, the confidence of the result is the lowest one
+// lowered again by the issues and warnings about the whole statement.
+func Score(result *types.ScanResult) {
+	if result.Warnings == nil {
+		result.Warnings = make(types.Warnings, 0)
+	}
+
+	for _, t := range result.Transactions {
+		t.Confidence = 1
+	}
+
+	statement := 1.0
+	lower := func(row int, code string) {
+		factor, ok := confidenceFactors[code]
+		if !ok {
+			return
+		}
+		if row < 0 || row >= len(result.Transactions) {
+			statement *= factor
+			return
+		}
+		result.Transactions[row].Confidence *= factor
+	}
+	for _, issue := range result.Issues {
+		lower(issue.Row, issue.Code)
+	}
+	for _, warning := range result.Warnings {
+		lower(warning.Row, warning.Code)
+	}
+
+	result.Confidence = statement
+	for _, t := range result.Transactions {
+		result.Confidence = min(result.Confidence, t.Confidence*statement)
+	}
+}
@@ -0,0 +1,133 @@
+// Package scannertest runs bank scanners against the statement fixtures
+// kept in the testdata directory of every bank package.
+package scannertest
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+	"github.com/mrrizkin/omniscan/pkg/pdf/provider/fixture"
+)
+
+// Expected is the result a fixture should scan to, only the info fields
+// written in the fixture are checked
+type Expected struct {
+	Info         map[string]interface{} `json:"info"`
+	Transactions []interface{}          `json:"transactions"`
+}
+
+// RunDir runs every fixture of dir as a subtest named after its file,
+// check is called with the result of every fixture when it is not nil
+func RunDir(t *testing.T, scanner types.BankScanner, dir string, check func(t *testing.T, result *types.ScanResult)) {
+	t.Helper()
+
+	filenames, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filenames) == 0 {
+		t.Fatalf("no fixture found in %s", dir)
+	}
+
+	for _, filename := range filenames {
+		name := strings.TrimSuffix(filepath.Base(filename), ".json")
+		t.Run(name, func(t *testing.T) {
+			result := Run(t, scanner, filename)
+			if check != nil {
+				check(t, result)
+			}
+		})
+	}
+}
+
+// Run scans the fixture at filename with scanner and checks the result
+// against the expected block of the fixture
+func Run(t testing.TB, scanner types.BankScanner, filename string) *types.ScanResult {
+	t.Helper()
+
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var file struct {
+		Expected Expected `json:"expected"`
+	}
+	if err := json.Unmarshal(b, &file); err != nil {
+		t.Fatalf("invalid fixture %s: %v", filename, err)
+	}
+
+	reader, err := fixture.NewReader(filename, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := scanner.Scan(reader)
+	if err != nil {
+		t.Fatalf("scan %s: %v", filename, err)
+	}
+
+	var got Expected
+	if err := roundTrip(map[string]interface{}{
+		"info":         result.Info,
+		"transactions": result.Transactions,
+	}, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	for key, want := range file.Expected.Info {
+		if !reflect.DeepEqual(got.Info[key], want) {
+			t.Errorf("info.%s = %v, want %v", key, got.Info[key], want)
+		}
+	}
+
+	if len(got.Transactions) != len(file.Expected.Transactions) {
+		t.Fatalf("got %d transactions, want %d", len(got.Transactions), len(file.Expected.Transactions))
+	}
+	for i, want := range file.Expected.Transactions {
+		if !reflect.DeepEqual(got.Transactions[i], want) {
+			gotJSON, _ := json.MarshalIndent(got.Transactions[i], "", "  ")
+			wantJSON, _ := json.MarshalIndent(want, "", "  ")
+			t.Errorf("transaction %d:\ngot  %s\nwant %s", i, gotJSON, wantJSON)
+		}
+	}
+
+	return result
+}
+
+// roundTrip converts v to the generic JSON values the fixture is read as
+func roundTrip(v interface{}, out interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, out)
+}
+
+// Description holds the fields a bank reads from the description lines of
+// a transaction
+type Description struct {
+	Channel             string
+	CounterpartyName    string
+	CounterpartyAccount string
+	CounterpartyBank    string
+	Reference           string
+	Remark              string
+}
+
+// DescriptionOf returns the description fields of t
+func DescriptionOf(t *types.Transaction) Description {
+	return Description{
+		Channel:             t.Channel,
+		CounterpartyName:    t.CounterpartyName,
+		CounterpartyAccount: t.CounterpartyAccount,
+		CounterpartyBank:    t.CounterpartyBank,
+		Reference:           t.Reference,
+		Remark:              t.Remark,
+	}
+}
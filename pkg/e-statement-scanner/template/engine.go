@@ -0,0 +1,499 @@
+package template
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	estatementtypes "github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+	"github.com/mrrizkin/omniscan/pkg/money"
+	"github.com/mrrizkin/omniscan/pkg/pdf"
+	"github.com/mrrizkin/omniscan/pkg/pdf/types"
+)
+
+type Transaction struct {
+	Date         time.Time
+	Description1 string
+	Description2 string
+	Branch       string
+	Change       money.Money
+	DirectionCr  *bool
+	Balance      money.Money
+	Sources      estatementtypes.Sources
+}
+
+type Transactions []*Transaction
+
+type Header struct {
+	Product  string
+	Rekening string
+	Periode  string
+	Currency string
+	Warnings estatementtypes.Warnings
+}
+
+var yearRegex = regexp.MustCompile(`\d\d\d\d`)
+var months = []string{
+	"JANUARI",
+	"FEBRUARI",
+	"MARET",
+	"APRIL",
+	"MEI",
+	"JUNI",
+	"JULI",
+	"AGUSTUS",
+	"SEPTEMBER",
+	"OKTOBER",
+	"NOVEMBER",
+	"DESEMBER",
+}
+
+func (tp *Template) processPdf(pdfR pdf.PDFReader) (Transactions, Header, error) {
+	totalPage := pdfR.NumPage()
+	transactions := make([]*Transaction, 0)
+	var currentTransaction *Transaction = nil
+	var isNew = false
+	dates := new(estatementtypes.YearResolver)
+	header := Header{}
+	ended := false
+	for pageIndex := 1; pageIndex <= totalPage; pageIndex++ {
+		p, err := pdfR.Page(pageIndex)
+		if err != nil {
+			return nil, header, err
+		}
+
+		sortedRows, err := p.GetTextByRow(tp.RowTolerance)
+		if err != nil {
+			return nil, header, err
+		}
+		aftHeader := false
+		shouldStopProcessing := false
+		for _, row := range sortedRows {
+			if aftHeader {
+				warn := func(code, message string) {
+					header.Warnings = header.Warnings.AddRow(code, pageIndex, row.Content, message)
+				}
+				isNew, currentTransaction, shouldStopProcessing = tp.ingestRow(
+					currentTransaction,
+					row,
+					dates,
+					warn,
+				)
+				if isNew {
+					transactions = append(transactions, currentTransaction)
+				}
+				if currentTransaction != nil && !shouldStopProcessing {
+					currentTransaction.Sources = currentTransaction.Sources.Add(pageIndex, row.Content)
+				}
+				if currentTransaction == nil && !shouldStopProcessing {
+					header.Warnings = header.Warnings.AddRow(
+						estatementtypes.WarningUnparsedRow,
+						pageIndex,
+						row.Content,
+						"row is before the first transaction",
+					)
+				}
+				if shouldStopProcessing {
+					ended = true
+					break
+				}
+				continue
+			}
+
+			if pageIndex == 1 {
+				tp.readHeader(&header, row)
+			}
+			if dates.Start.IsZero() && tp.YearFrom == YearFromMonthHeader {
+				if start, ok := monthHeaderStart(row); ok {
+					// the statement covers the month
+					dates.Start = start
+					dates.End = start.AddDate(0, 1, -1)
+				}
+			}
+			if tp.isTableHeader(row) {
+				aftHeader = true
+				if dates.Start.IsZero() && dates.Year == 0 && tp.YearFrom == YearFromPeriod {
+					tp.readPeriod(dates, header.Periode)
+				}
+			}
+		}
+		if !aftHeader && !ended && len(sortedRows) > 0 {
+			header.Warnings = append(header.Warnings, estatementtypes.Warning{
+				Row:     -1,
+				Code:    estatementtypes.WarningSkippedRows,
+				Message: fmt.Sprintf("table header not found, %d rows skipped", len(sortedRows)),
+				Page:    pageIndex,
+			})
+		}
+	}
+
+	tp.fillOpeningDate(transactions, dates)
+	return transactions, header, nil
+}
+
+// ingestRow follows the same rules as the bank packages: a row with a
+// date starts a new transaction, any other row adds detail to prevT
+func (tp *Template) ingestRow(
+	prevT *Transaction,
+	row *types.Row,
+	dates *estatementtypes.YearResolver,
+	warn estatementtypes.WarnFunc,
+) (isNew bool, t *Transaction, shouldStopProcessing bool) {
+	words := make(types.TextHorizontal, len(row.Content))
+	copy(words, row.Content)
+	if len(words) == 0 {
+		return
+	}
+	if matchMarker(words, tp.StopMarkers) {
+		shouldStopProcessing = true
+		return
+	}
+
+	firstWord := words[0]
+	var date time.Time
+	hasDate := false
+	if tp.Columns.Date.Is(firstWord.X) {
+		date, hasDate = tp.parseDate(dates, strings.Fields(firstWord.S)...)
+		if !hasDate && estatementtypes.LooksLikeDate(firstWord.S) {
+			warn(estatementtypes.WarningUnparsedDate, fmt.Sprintf("date %q can't be read", firstWord.S))
+		}
+	}
+	switch {
+	case hasDate:
+		isNew = true
+		words = words[1:]
+		t = &Transaction{
+			Date: date,
+		}
+
+		if len(words) > 0 && tp.Columns.ValueDate.Is(words[0].X) {
+			words = words[1:]
+		}
+	case matchMarker(words, tp.OpeningMarkers):
+		isNew = true
+		t = &Transaction{
+			Description1: "SALDO AWAL",
+		}
+		if balance, ok := tp.AmountFormat.Parse(words[len(words)-1].S); ok {
+			t.Balance = balance
+		} else if estatementtypes.LooksLikeAmount(words[len(words)-1].S) {
+			warn(estatementtypes.WarningUnparsedAmount, fmt.Sprintf("balance %q can't be read", words[len(words)-1].S))
+		}
+		return
+	default:
+		if prevT == nil {
+			return
+		}
+		t = prevT
+	}
+
+	if len(words) > 0 {
+		lastWord := words[len(words)-1]
+		balance, hasBalance := tp.AmountFormat.Parse(lastWord.S)
+		if hasBalance && tp.Columns.Balance.Is(lastWord.X) {
+			t.Balance = balance
+			words = words[:len(words)-1]
+		} else if tp.Columns.Balance.Is(lastWord.X) && estatementtypes.LooksLikeAmount(lastWord.S) {
+			warn(estatementtypes.WarningUnparsedAmount, fmt.Sprintf("balance %q can't be read", lastWord.S))
+			words = words[:len(words)-1]
+		}
+	}
+
+	tp.readSupplementary(t, words, warn)
+	return
+}
+
+func (tp *Template) readSupplementary(
+	t *Transaction,
+	words types.TextHorizontal,
+	warn estatementtypes.WarnFunc,
+) {
+	for i, word := range words {
+		if tp.Columns.Credit.Is(word.X) {
+			tp.readAmount(t, word.S, true, warn)
+			continue
+		}
+		if tp.Columns.Debit.Is(word.X) {
+			tp.readAmount(t, word.S, false, warn)
+			continue
+		}
+		if tp.Columns.Amount.Is(word.X) {
+			if isCr, ok := tp.Direction.parse(word.S); ok {
+				t.DirectionCr = &isCr
+				continue
+			}
+
+			amount, ok := tp.AmountFormat.Parse(word.S)
+			if !ok {
+				if estatementtypes.LooksLikeAmount(word.S) {
+					warn(estatementtypes.WarningUnparsedAmount, fmt.Sprintf("amount %q can't be read", word.S))
+				}
+				continue
+			}
+
+			t.Change = amount
+			if t.DirectionCr == nil {
+				isCr, ok := tp.Direction.find(words[i+1:])
+				if ok {
+					t.DirectionCr = &isCr
+				}
+			}
+			continue
+		}
+		if tp.Columns.Branch.Is(word.X) {
+			t.Branch = word.S
+		}
+		if tp.Columns.Description1.Is(word.X) {
+			t.Description1 = appendLine(t.Description1, word.S)
+		}
+		if tp.Columns.Description2.Is(word.X) {
+			t.Description2 = appendLine(t.Description2, word.S)
+		}
+	}
+}
+
+// readAmount reads a debit or credit column, statements printing both
+// columns on every row leave 0 in the unused one
+func (tp *Template) readAmount(t *Transaction, s string, isCr bool, warn estatementtypes.WarnFunc) {
+	amount, ok := tp.AmountFormat.Parse(s)
+	if !ok && estatementtypes.LooksLikeAmount(s) {
+		warn(estatementtypes.WarningUnparsedAmount, fmt.Sprintf("amount %q can't be read", s))
+	}
+	if !ok || amount == 0 {
+		return
+	}
+
+	t.DirectionCr = &isCr
+	t.Change = amount
+}
+
+func (d Direction) parse(s string) (isCr bool, ok bool) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	for _, marker := range d.DebitMarkers {
+		if s == strings.ToUpper(marker) {
+			return false, true
+		}
+	}
+	for _, marker := range d.CreditMarkers {
+		if s == strings.ToUpper(marker) {
+			return true, true
+		}
+	}
+	return false, false
+}
+
+// find looks for a direction marker after the amount, falling back to the
+// default direction
+func (d Direction) find(words types.TextHorizontal) (isCr bool, ok bool) {
+	for _, word := range words {
+		if isCr, ok := d.parse(word.S); ok {
+			return isCr, true
+		}
+	}
+
+	switch d.Default {
+	case "credit":
+		return true, true
+	case "debit":
+		return false, true
+	}
+	return false, false
+}
+
+func (tp *Template) readHeader(header *Header, row *types.Row) {
+	if len(row.Content) == 0 {
+		return
+	}
+
+	for _, field := range tp.Header {
+		if !strings.Contains(row.Content[0].S, field.Label) {
+			continue
+		}
+
+		value := ""
+		switch {
+		case field.Self:
+			if len(row.Content) == 1 {
+				value = row.Content[0].S
+			}
+		case field.Index > 0:
+			if len(row.Content) > field.Index {
+				value = row.Content[field.Index].S
+			}
+		default:
+			value = estatementtypes.ValueAfterLabel(row.Content)
+		}
+
+		if value == "" {
+			continue
+		}
+
+		switch field.Field {
+		case "product":
+			header.Product = value
+		case "rekening":
+			header.Rekening = value
+		case "periode":
+			header.Periode = value
+		case "currency":
+			header.Currency = value
+		}
+		return
+	}
+}
+
+func (tp *Template) isTableHeader(row *types.Row) bool {
+	text := make([]string, len(row.Content))
+	for i, word := range row.Content {
+		text[i] = strings.ToUpper(word.S)
+	}
+
+	rest := strings.Join(text, " ")
+	for _, column := range tp.TableHeader {
+		index := strings.Index(rest, strings.ToUpper(column))
+		if index == -1 {
+			return false
+		}
+		rest = rest[index+len(column):]
+	}
+	return true
+}
+
+// parseDate returns the first word matching one of the date formats,
+// the year of formats without one is picked by dates
+func (tp *Template) parseDate(dates *estatementtypes.YearResolver, words ...string) (time.Time, bool) {
+	for _, word := range words {
+		for _, layout := range tp.DateFormats {
+			date, err := time.Parse(layout, word)
+			if err != nil {
+				continue
+			}
+
+			if !hasYear(layout) {
+				date = dates.Resolve(date)
+			}
+			return date, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// periodFormats are the layouts the period dates are tried with after
+// the date formats of the template holding a year
+var periodFormats = []string{
+	"02/01/2006",
+	"02-01-2006",
+	"2006-01-02",
+}
+
+// readPeriod seeds dates with the first and the last date of the period,
+// or with the year alone when the period holds no readable date
+func (tp *Template) readPeriod(dates *estatementtypes.YearResolver, periode string) {
+	layouts := make([]string, 0, len(tp.DateFormats)+len(periodFormats))
+	for _, layout := range tp.DateFormats {
+		if hasYear(layout) {
+			layouts = append(layouts, layout)
+		}
+	}
+	layouts = append(layouts, periodFormats...)
+
+	found := make([]time.Time, 0, 2)
+	for _, word := range strings.Fields(periode) {
+		for _, layout := range layouts {
+			date, err := time.Parse(layout, word)
+			if err == nil {
+				found = append(found, date)
+				break
+			}
+		}
+	}
+	if len(found) > 0 {
+		dates.Start, dates.End = found[0], found[len(found)-1]
+		return
+	}
+
+	dates.Year, _ = strconv.Atoi(periodYear(periode))
+}
+
+// the opening balance row has no date, it is dated on the first day of
+// the period, or on the first transaction when the period is unreadable
+func (tp *Template) fillOpeningDate(transactions Transactions, dates *estatementtypes.YearResolver) {
+	for i, t := range transactions {
+		if !t.Date.IsZero() {
+			continue
+		}
+
+		if !dates.Start.IsZero() {
+			t.Date = dates.Start
+			continue
+		}
+
+		for _, next := range transactions[i+1:] {
+			if !next.Date.IsZero() {
+				t.Date = next.Date
+				break
+			}
+		}
+	}
+}
+
+func matchMarker(words types.TextHorizontal, markers []Marker) bool {
+	for _, word := range words {
+		text := strings.ToUpper(word.S)
+		for _, marker := range markers {
+			if !strings.Contains(text, strings.ToUpper(marker.Text)) {
+				continue
+			}
+			if marker.Column == nil || marker.Column.Is(word.X) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// monthHeaderStart reads the first day of the month of rows like
+// "DESEMBER 2024"
+func monthHeaderStart(row *types.Row) (time.Time, bool) {
+	for _, word := range row.Content {
+		for monthIndex, month := range months {
+			if !strings.Contains(word.S, month) {
+				continue
+			}
+
+			year := strings.TrimSpace(strings.TrimPrefix(word.S, month))
+			if !yearRegex.MatchString(year) {
+				continue
+			}
+			y, err := strconv.Atoi(yearRegex.FindString(year))
+			if err != nil {
+				continue
+			}
+			return time.Date(y, time.Month(monthIndex+1), 1, 0, 0, 0, 0, time.UTC), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// periodYear returns the last year written in the period
+func periodYear(periode string) string {
+	years := yearRegex.FindAllString(periode, -1)
+	if len(years) == 0 {
+		return ""
+	}
+	return years[len(years)-1]
+}
+
+// hasYear tells whether a date layout holds the year
+func hasYear(layout string) bool {
+	return strings.Contains(layout, "06")
+}
+
+func appendLine(text, line string) string {
+	if text == "" {
+		return line
+	}
+	return text + "\n" + line
+}
@@ -0,0 +1,117 @@
+package template
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/scannertest"
+	estatementtypes "github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+	"github.com/mrrizkin/omniscan/pkg/pdf/types"
+)
+
+// every shipped template is run against the fixture named after its id
+func TestTemplateFixtures(t *testing.T) {
+	scanners, err := LoadDir(templatesDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range scanners {
+		t.Run(s.ID(), func(t *testing.T) {
+			scannertest.Run(t, s, "testdata/"+s.ID()+".json")
+		})
+	}
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		name    string
+		formats []string
+		periode string
+		words   []string
+		want    []time.Time
+	}{
+		{
+			name:    "year in the date",
+			formats: []string{"02/01/2006"},
+			words:   []string{"31/12/2024", "02/01/2025"},
+			want:    []time.Time{date(2024, 12, 31), date(2025, 1, 2)},
+		},
+		{
+			name:    "period crossing the year",
+			formats: []string{"02/01"},
+			periode: "15/12/2024 - 14/01/2025",
+			words:   []string{"16/12", "31/12", "05/01"},
+			want:    []time.Time{date(2024, 12, 16), date(2024, 12, 31), date(2025, 1, 5)},
+		},
+		{
+			name:    "period holding only the year",
+			formats: []string{"02/01"},
+			periode: "JANUARI 2024",
+			words:   []string{"02/01", "31/01"},
+			want:    []time.Time{date(2024, 1, 2), date(2024, 1, 31)},
+		},
+		{
+			name:    "no period rolls over december",
+			formats: []string{"02/01"},
+			words:   []string{"30/12", "02/01"},
+			want:    []time.Time{date(1900, 12, 30), date(1901, 1, 2)},
+		},
+		{
+			name:    "period read with the template format",
+			formats: []string{"02 Jan", "02 Jan 2006"},
+			periode: "01 Feb 2024 - 29 Feb 2024",
+			words:   []string{"29 Feb"},
+			want:    []time.Time{date(2024, 2, 29)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tp := &Template{DateFormats: tt.formats, YearFrom: YearFromPeriod}
+			dates := new(estatementtypes.YearResolver)
+			tp.readPeriod(dates, tt.periode)
+
+			for i, word := range tt.words {
+				got, ok := tp.parseDate(dates, word)
+				if !ok {
+					t.Fatalf("parseDate(%q) failed", word)
+				}
+				if !got.Equal(tt.want[i]) {
+					t.Errorf("parseDate(%q) = %s, want %s", word, got.Format(time.DateOnly), tt.want[i].Format(time.DateOnly))
+				}
+			}
+		})
+	}
+}
+
+func TestMonthHeaderStart(t *testing.T) {
+	tests := []struct {
+		name   string
+		words  []string
+		want   time.Time
+		wantOk bool
+	}{
+		{name: "month and year", words: []string{"PERIODE", ":", "DESEMBER 2024"}, want: date(2024, 12, 1), wantOk: true},
+		{name: "month alone", words: []string{"DESEMBER"}},
+		{name: "no month", words: []string{"MATA UANG", ":", "IDR"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := &types.Row{}
+			for _, s := range tt.words {
+				row.Content = append(row.Content, types.Text{S: s})
+			}
+
+			got, ok := monthHeaderStart(row)
+			if ok != tt.wantOk || !got.Equal(tt.want) {
+				t.Errorf("monthHeaderStart() = %s, %v, want %s, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
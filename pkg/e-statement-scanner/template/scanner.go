@@ -0,0 +1,97 @@
+package template
+
+import (
+	"github.com/mrrizkin/omniscan/pkg/e-statement-scanner/types"
+	"github.com/mrrizkin/omniscan/pkg/pdf"
+	pdftypes "github.com/mrrizkin/omniscan/pkg/pdf/types"
+)
+
+// Scanner runs a Template through a pdf.PDFReader, it is registered like
+// any bank package scanner.
+type Scanner struct {
+	template *Template
+}
+
+func New(template *Template) *Scanner {
+	return &Scanner{template: template}
+}
+
+func (s *Scanner) ID() string {
+	return s.template.ID
+}
+
+func (s *Scanner) Name() string {
+	return s.template.Name
+}
+
+func (s *Scanner) Products() []string {
+	return s.template.Products
+}
+
+func (s *Scanner) Scan(pdfReader pdf.PDFReader) (*types.ScanResult, error) {
+	trx, header, err := s.template.processPdf(pdfReader)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.maptrx(header, trx), nil
+}
+
+func (s *Scanner) Detect(firstPage pdftypes.Rows, metadata *pdf.Metadata) float64 {
+	score := 0.0
+	for _, row := range firstPage {
+		if s.template.isTableHeader(row) {
+			score += 0.4
+			break
+		}
+	}
+	if types.ContainsText(firstPage, s.template.Detect.Keywords...) {
+		score += 0.3
+	}
+	for _, label := range s.template.Detect.Labels {
+		if types.HasLabel(firstPage, label) {
+			score += 0.2
+			break
+		}
+	}
+	if types.MetadataContains(metadata, s.template.Detect.Metadata...) {
+		score += 0.1
+	}
+	return score
+}
+
+func (s *Scanner) maptrx(header Header, trxs Transactions) *types.ScanResult {
+	res := new(types.ScanResult)
+	res.Transactions = make([]*types.Transaction, len(trxs))
+	for i, t := range trxs {
+		trxType := ""
+		if t.DirectionCr != nil {
+			if *t.DirectionCr {
+				trxType = "credit"
+			} else {
+				trxType = "debit"
+			}
+		}
+
+		res.Transactions[i] = &types.Transaction{
+			Date:            t.Date,
+			Description1:    t.Description1,
+			Description2:    t.Description2,
+			Branch:          t.Branch,
+			Change:          t.Change,
+			TransactionType: trxType,
+			Balance:         t.Balance,
+			Sources:         t.Sources,
+		}
+	}
+
+	res.Info.Bank = s.template.Name
+	res.Info.Produk = header.Product
+	res.Info.Rekening = header.Rekening
+	res.Info.Periode = header.Periode
+	res.Info.Currency = header.Currency
+
+	res.Warnings = header.Warnings
+
+	return res
+}
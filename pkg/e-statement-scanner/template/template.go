@@ -0,0 +1,216 @@
+package template
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mrrizkin/omniscan/pkg/money"
+)
+
+// Template describes the layout of a bank statement so it can be parsed
+// without writing a bank package, see resources/templates/e-statement for
+// examples.
+type (
+	Template struct {
+		ID             string        `json:"id"`
+		Name           string        `json:"name"`
+		Products       []string      `json:"products"`
+		RowTolerance   float64       `json:"row_tolerance"`
+		Header         []HeaderField `json:"header"`
+		TableHeader    []string      `json:"table_header"`
+		Columns        Columns       `json:"columns"`
+		DateFormats    []string      `json:"date_formats"`
+		YearFrom       string        `json:"year_from"`
+		AmountFormat   AmountFormat  `json:"amount_format"`
+		Direction      Direction     `json:"direction"`
+		OpeningMarkers []Marker      `json:"opening_markers"`
+		StopMarkers    []Marker      `json:"stop_markers"`
+		Detect         Detect        `json:"detect"`
+	}
+
+	// HeaderField reads one of the statement header values, the value is
+	// the word at Index of the row starting with Label, or every word
+	// after the label when Index is 0. With Self the label row is the
+	// value itself, e.g. "REKENING TAHAPAN".
+	HeaderField struct {
+		Field string `json:"field"`
+		Label string `json:"label"`
+		Index int    `json:"index"`
+		Self  bool   `json:"self"`
+	}
+
+	Columns struct {
+		Date         *Column `json:"date"`
+		ValueDate    *Column `json:"value_date"`
+		Description1 *Column `json:"description1"`
+		Description2 *Column `json:"description2"`
+		Branch       *Column `json:"branch"`
+		Amount       *Column `json:"amount"`
+		Debit        *Column `json:"debit"`
+		Credit       *Column `json:"credit"`
+		Balance      *Column `json:"balance"`
+	}
+
+	// Column anchors a column on the x axis. A left aligned column matches
+	// words starting within Tolerance of the anchor, a right aligned column
+	// matches words starting after the anchor and before Until when set.
+	Column struct {
+		Anchor    float64 `json:"anchor"`
+		Align     string  `json:"align"`
+		Tolerance float64 `json:"tolerance"`
+		Until     float64 `json:"until"`
+	}
+
+	AmountFormat struct {
+		Thousand string `json:"thousand"`
+		Decimal  string `json:"decimal"`
+	}
+
+	// Direction tells how debit and credit are told apart when the
+	// statement has a single amount column.
+	Direction struct {
+		DebitMarkers  []string `json:"debit_markers"`
+		CreditMarkers []string `json:"credit_markers"`
+		Default       string   `json:"default"`
+	}
+
+	Marker struct {
+		Text   string  `json:"text"`
+		Column *Column `json:"column"`
+	}
+
+	Detect struct {
+		Keywords []string `json:"keywords"`
+		Labels   []string `json:"labels"`
+		Metadata []string `json:"metadata"`
+	}
+)
+
+const (
+	defaultTolerance    = 5.0
+	defaultRowTolerance = 1.0
+
+	YearFromMonthHeader = "month_header"
+	YearFromPeriod      = "period"
+)
+
+func (c *Column) Is(x float64) bool {
+	if c == nil {
+		return false
+	}
+
+	if c.Align == "right" {
+		return x > c.Anchor && (c.Until == 0 || x <= c.Until)
+	}
+
+	tolerance := c.Tolerance
+	if tolerance == 0 {
+		tolerance = defaultTolerance
+	}
+	return math.Abs(c.Anchor-x) < tolerance
+}
+
+func (f AmountFormat) Parse(s string) (money.Money, bool) {
+	thousand, decimal := f.Thousand, f.Decimal
+	if thousand == "" && decimal == "" {
+		thousand, decimal = ",", "."
+	}
+
+	s = strings.TrimSpace(s)
+	if thousand != "" {
+		s = strings.ReplaceAll(s, thousand, "")
+	}
+	if decimal != "" && decimal != "." {
+		s = strings.ReplaceAll(s, decimal, ".")
+	}
+
+	amount, err := money.Parse(s)
+	return amount, err == nil
+}
+
+func (t *Template) Validate() error {
+	if t.ID == "" {
+		return errors.New("id is required")
+	}
+	if t.Name == "" {
+		return errors.New("name is required")
+	}
+	if len(t.TableHeader) == 0 {
+		return errors.New("table_header is required")
+	}
+	if t.Columns.Date == nil {
+		return errors.New("columns.date is required")
+	}
+	if t.Columns.Amount == nil && (t.Columns.Debit == nil || t.Columns.Credit == nil) {
+		return errors.New("columns.amount or both columns.debit and columns.credit are required")
+	}
+	if len(t.DateFormats) == 0 {
+		return errors.New("date_formats is required")
+	}
+	switch t.YearFrom {
+	case "", YearFromMonthHeader, YearFromPeriod:
+	default:
+		return fmt.Errorf("unknown year_from: %s", t.YearFrom)
+	}
+	for _, field := range t.Header {
+		switch field.Field {
+		case "product", "rekening", "periode", "currency":
+		default:
+			return fmt.Errorf("unknown header field: %s", field.Field)
+		}
+	}
+	return nil
+}
+
+func Parse(b []byte) (*Template, error) {
+	template := new(Template)
+	if err := json.Unmarshal(b, template); err != nil {
+		return nil, err
+	}
+
+	if err := template.Validate(); err != nil {
+		return nil, err
+	}
+
+	if template.RowTolerance == 0 {
+		template.RowTolerance = defaultRowTolerance
+	}
+
+	return template, nil
+}
+
+// LoadDir reads every *.json template in dir, an empty dir loads nothing.
+func LoadDir(dir string) ([]*Scanner, error) {
+	if dir == "" {
+		return nil, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	scanners := make([]*Scanner, 0, len(files))
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		template, err := Parse(b)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template %s: %w", file, err)
+		}
+
+		scanners = append(scanners, New(template))
+	}
+
+	return scanners, nil
+}
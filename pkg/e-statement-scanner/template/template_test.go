@@ -0,0 +1,198 @@
+package template
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mrrizkin/omniscan/pkg/money"
+)
+
+// the templates shipped with omniscan
+const templatesDir = "../../../resources/templates/e-statement"
+
+func validTemplate() *Template {
+	return &Template{
+		ID:          "test",
+		Name:        "Test",
+		TableHeader: []string{"TANGGAL", "KETERANGAN", "MUTASI"},
+		Columns: Columns{
+			Date:   &Column{Anchor: 40},
+			Amount: &Column{Anchor: 300, Align: "right"},
+		},
+		DateFormats: []string{"02/01/2006"},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		edit    func(tp *Template)
+		wantErr string
+	}{
+		{name: "valid", edit: func(tp *Template) {}},
+		{
+			name: "debit and credit columns",
+			edit: func(tp *Template) {
+				tp.Columns.Amount = nil
+				tp.Columns.Debit = &Column{Anchor: 300, Align: "right", Until: 400}
+				tp.Columns.Credit = &Column{Anchor: 400, Align: "right"}
+			},
+		},
+		{name: "no id", edit: func(tp *Template) { tp.ID = "" }, wantErr: "id is required"},
+		{name: "no name", edit: func(tp *Template) { tp.Name = "" }, wantErr: "name is required"},
+		{name: "no table header", edit: func(tp *Template) { tp.TableHeader = nil }, wantErr: "table_header is required"},
+		{name: "no date column", edit: func(tp *Template) { tp.Columns.Date = nil }, wantErr: "columns.date is required"},
+		{
+			name: "debit column alone",
+			edit: func(tp *Template) {
+				tp.Columns.Amount = nil
+				tp.Columns.Debit = &Column{Anchor: 300, Align: "right"}
+			},
+			wantErr: "columns.amount or both columns.debit and columns.credit are required",
+		},
+		{name: "no date format", edit: func(tp *Template) { tp.DateFormats = nil }, wantErr: "date_formats is required"},
+		{name: "unknown year_from", edit: func(tp *Template) { tp.YearFrom = "footer" }, wantErr: "unknown year_from: footer"},
+		{
+			name:    "unknown header field",
+			edit:    func(tp *Template) { tp.Header = []HeaderField{{Field: "holder", Label: "Nama"}} },
+			wantErr: "unknown header field: holder",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tp := validTemplate()
+			tt.edit(tp)
+
+			err := tp.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("Validate() = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAmountFormatParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		format AmountFormat
+		s      string
+		want   money.Money
+		wantOk bool
+	}{
+		{name: "default format", s: "1,250,000.50", want: money.FromFloat(1250000.5), wantOk: true},
+		{name: "default format without decimals", s: "1,250,000", want: money.FromInt(1250000), wantOk: true},
+		{name: "spaces around", s: " 75,000.00 ", want: money.FromInt(75000), wantOk: true},
+		{
+			name:   "decimal comma",
+			format: AmountFormat{Thousand: ".", Decimal: ","},
+			s:      "1.250.000,50",
+			want:   money.FromFloat(1250000.5),
+			wantOk: true,
+		},
+		{
+			name:   "no thousand separator",
+			format: AmountFormat{Decimal: ","},
+			s:      "1250000,50",
+			want:   money.FromFloat(1250000.5),
+			wantOk: true,
+		},
+		{name: "negative", s: "-12,500.00", want: money.FromInt(-12500), wantOk: true},
+		{name: "text", s: "DB"},
+		{name: "empty", s: ""},
+		{
+			name:   "decimal comma read with the default format",
+			s:      "1.250.000,50",
+			wantOk: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.format.Parse(tt.s)
+			if ok != tt.wantOk {
+				t.Fatalf("Parse(%q) ok = %v, want %v", tt.s, ok, tt.wantOk)
+			}
+			if ok && got != tt.want {
+				t.Errorf("Parse(%q) = %v, want %v", tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColumnIs(t *testing.T) {
+	tests := []struct {
+		name   string
+		column *Column
+		x      float64
+		want   bool
+	}{
+		{name: "nil column", x: 40},
+		{name: "left at the anchor", column: &Column{Anchor: 40}, x: 40, want: true},
+		{name: "left within the default tolerance", column: &Column{Anchor: 40}, x: 44.9, want: true},
+		{name: "left before the anchor", column: &Column{Anchor: 40}, x: 35.1, want: true},
+		{name: "left past the default tolerance", column: &Column{Anchor: 40}, x: 45},
+		{name: "left within a wider tolerance", column: &Column{Anchor: 40, Tolerance: 10}, x: 49, want: true},
+		{name: "right at the anchor", column: &Column{Anchor: 300, Align: "right"}, x: 300},
+		{name: "right past the anchor", column: &Column{Anchor: 300, Align: "right"}, x: 560, want: true},
+		{name: "right before until", column: &Column{Anchor: 300, Align: "right", Until: 400}, x: 400, want: true},
+		{name: "right past until", column: &Column{Anchor: 300, Align: "right", Until: 400}, x: 400.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.column.Is(tt.x); got != tt.want {
+				t.Errorf("Is(%v) = %v, want %v", tt.x, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	t.Run("no dir", func(t *testing.T) {
+		scanners, err := LoadDir("")
+		if err != nil || scanners != nil {
+			t.Fatalf("LoadDir() = %v, %v, want nil, nil", scanners, err)
+		}
+	})
+
+	t.Run("shipped templates", func(t *testing.T) {
+		scanners, err := LoadDir(templatesDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ids := make([]string, len(scanners))
+		for i, s := range scanners {
+			ids[i] = s.ID()
+		}
+		if got, want := strings.Join(ids, ","), "bca-template,mandiri-template"; got != want {
+			t.Errorf("ids = %s, want %s", got, want)
+		}
+		for _, s := range scanners {
+			if s.template.RowTolerance == 0 {
+				t.Errorf("%s row tolerance is not defaulted", s.ID())
+			}
+		}
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte(`{"id": "broken"}`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := LoadDir(dir)
+		if err == nil || !strings.Contains(err.Error(), "broken.json") {
+			t.Fatalf("LoadDir() = %v, want an error naming broken.json", err)
+		}
+	})
+}
@@ -0,0 +1,18 @@
+package types
+
+import "strings"
+
+// DescriptionLines splits the descriptions into their trimmed lines,
+// leaving out the empty ones
+func DescriptionLines(descriptions ...string) []string {
+	lines := make([]string, 0)
+	for _, description := range descriptions {
+		for _, line := range strings.Split(description, "\n") {
+			line = strings.TrimSpace(line)
+			if line != "" {
+				lines = append(lines, line)
+			}
+		}
+	}
+	return lines
+}
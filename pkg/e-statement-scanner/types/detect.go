@@ -0,0 +1,82 @@
+package types
+
+import (
+	"strings"
+
+	"github.com/mrrizkin/omniscan/pkg/pdf"
+	pdftypes "github.com/mrrizkin/omniscan/pkg/pdf/types"
+)
+
+// HasColumns reports whether one of the rows is a table header whose
+// words start with the given column names, in order.
+func HasColumns(rows pdftypes.Rows, columns ...string) bool {
+	for _, row := range rows {
+		if len(row.Content) < len(columns) {
+			continue
+		}
+
+		match := 0
+		for i, column := range columns {
+			if !strings.Contains(strings.ToUpper(row.Content[i].S), column) {
+				break
+			}
+			match++
+		}
+
+		if match == len(columns) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasLabel reports whether a row starts with the given label, this is how
+// the statement headers ("NO. REKENING", "Nomor Rekening", ...) are laid out.
+func HasLabel(rows pdftypes.Rows, label string) bool {
+	for _, row := range rows {
+		if len(row.Content) > 0 && strings.Contains(row.Content[0].S, label) {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsText reports whether any word of the rows contains one of the
+// needles, ignoring case.
+func ContainsText(rows pdftypes.Rows, needles ...string) bool {
+	for _, row := range rows {
+		for _, word := range row.Content {
+			text := strings.ToUpper(word.S)
+			for _, needle := range needles {
+				if strings.Contains(text, strings.ToUpper(needle)) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// MetadataContains reports whether the producer, creator, title or author
+// of the document contains one of the needles, ignoring case.
+func MetadataContains(metadata *pdf.Metadata, needles ...string) bool {
+	if metadata == nil || metadata.PDFInfo == nil {
+		return false
+	}
+
+	fields := []string{
+		metadata.Producer,
+		metadata.Creator,
+		metadata.Title,
+		metadata.Author,
+	}
+	for _, field := range fields {
+		field = strings.ToUpper(field)
+		for _, needle := range needles {
+			if strings.Contains(field, strings.ToUpper(needle)) {
+				return true
+			}
+		}
+	}
+	return false
+}
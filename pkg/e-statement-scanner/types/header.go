@@ -0,0 +1,30 @@
+package types
+
+import (
+	"strings"
+
+	pdftypes "github.com/mrrizkin/omniscan/pkg/pdf/types"
+)
+
+// ValueAfterLabel joins the words following the label of a header row,
+// leaving out the ":" separator.
+func ValueAfterLabel(words pdftypes.TextHorizontal) string {
+	if len(words) == 0 {
+		return ""
+	}
+
+	values := make([]string, 0, len(words))
+	for _, word := range words[1:] {
+		value := strings.TrimSpace(strings.TrimPrefix(word.S, ":"))
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+	return strings.Join(values, " ")
+}
+
+// AccountType is the product without the "REKENING" prefix, e.g. TAHAPAN
+// for BCA's REKENING TAHAPAN or TABUNGAN for Mandiri's REKENING TABUNGAN.
+func AccountType(product string) string {
+	return strings.TrimSpace(strings.TrimPrefix(strings.ToUpper(product), "REKENING"))
+}
@@ -0,0 +1,52 @@
+package types
+
+import (
+	"testing"
+
+	pdftypes "github.com/mrrizkin/omniscan/pkg/pdf/types"
+)
+
+func TestValueAfterLabel(t *testing.T) {
+	tests := []struct {
+		name  string
+		words []string
+		want  string
+	}{
+		{name: "separate separator", words: []string{"Mata Uang", ":", "IDR"}, want: "IDR"},
+		{name: "separator in the value", words: []string{"Nama", ": SITI RAHAYU"}, want: "SITI RAHAYU"},
+		{name: "several words", words: []string{"Cabang", ":", "KCP", "JAKARTA SUDIRMAN"}, want: "KCP JAKARTA SUDIRMAN"},
+		{name: "label only", words: []string{"Periode", ":"}, want: ""},
+		{name: "empty row", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			words := make(pdftypes.TextHorizontal, len(tt.words))
+			for i, s := range tt.words {
+				words[i] = pdftypes.Text{S: s}
+			}
+			if got := ValueAfterLabel(words); got != tt.want {
+				t.Errorf("ValueAfterLabel() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAccountType(t *testing.T) {
+	tests := []struct {
+		product string
+		want    string
+	}{
+		{product: "REKENING TAHAPAN", want: "TAHAPAN"},
+		{product: "Rekening Tabungan", want: "TABUNGAN"},
+		{product: "TAPLUS", want: "TAPLUS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.product, func(t *testing.T) {
+			if got := AccountType(tt.product); got != tt.want {
+				t.Errorf("AccountType(%q) = %q, want %q", tt.product, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	pdftypes "github.com/mrrizkin/omniscan/pkg/pdf/types"
+)
+
+// Source is the area of a page a transaction was read from, in PDF points
+// with the origin at the bottom left corner of the page. Page starts at 1.
+type Source struct {
+	Page int     `json:"page"`
+	X0   float64 `json:"x0"`
+	Y0   float64 `json:"y0"`
+	X1   float64 `json:"x1"`
+	Y1   float64 `json:"y1"`
+}
+
+// Sources holds one Source per page, a transaction continued on the next
+// page has two of them.
+type Sources []Source
+
+// Add extends the area of page to cover words.
+func (s Sources) Add(page int, words pdftypes.TextHorizontal) Sources {
+	for _, word := range words {
+		x0, y0, x1, y1 := word.Bounds()
+
+		i := len(s) - 1
+		if i < 0 || s[i].Page != page {
+			s = append(s, Source{Page: page, X0: x0, Y0: y0, X1: x1, Y1: y1})
+			continue
+		}
+
+		s[i].X0 = min(s[i].X0, x0)
+		s[i].Y0 = min(s[i].Y0, y0)
+		s[i].X1 = max(s[i].X1, x1)
+		s[i].Y1 = max(s[i].Y1, y1)
+	}
+	return s
+}
+
+// Scan reads the sources stored as JSON text.
+func (s *Sources) Scan(value interface{}) error {
+	var b []byte
+	switch v := value.(type) {
+	case nil:
+		*s = nil
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return fmt.Errorf("sources: unsupported scan type %T", value)
+	}
+
+	if len(b) == 0 {
+		*s = nil
+		return nil
+	}
+	return json.Unmarshal(b, s)
+}
+
+// Value stores the sources as JSON text.
+func (s Sources) Value() (driver.Value, error) {
+	if len(s) == 0 {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
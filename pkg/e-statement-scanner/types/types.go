@@ -3,28 +3,150 @@ package types
 import (
 	"time"
 
+	"github.com/mrrizkin/omniscan/pkg/money"
 	"github.com/mrrizkin/omniscan/pkg/pdf"
+	pdftypes "github.com/mrrizkin/omniscan/pkg/pdf/types"
 )
 
 type Transaction struct {
-	Date            time.Time `json:"date,omitempty"`
-	Description1    string    `json:"description1,omitempty"`
-	Description2    string    `json:"description2,omitempty"`
-	Branch          string    `json:"branch,omitempty"`
-	Change          float64   `json:"change,omitempty"`
-	TransactionType string    `json:"transaction_type,omitempty"`
-	Balance         float64   `json:"balance,omitempty"`
+	Date            time.Time   `json:"date,omitempty"`
+	Description1    string      `json:"description1,omitempty"`
+	Description2    string      `json:"description2,omitempty"`
+	Branch          string      `json:"branch,omitempty"`
+	Change          money.Money `json:"change"`
+	TransactionType string      `json:"transaction_type,omitempty"`
+	Balance         money.Money `json:"balance"`
+	Currency        string      `json:"currency,omitempty"`
+
+	// fields read from the description by the bank package, empty when
+	// the description doesn't carry them
+	Channel             string `json:"channel,omitempty"`
+	CounterpartyName    string `json:"counterparty_name,omitempty"`
+	CounterpartyAccount string `json:"counterparty_account,omitempty"`
+	CounterpartyBank    string `json:"counterparty_bank,omitempty"`
+	Reference           string `json:"reference,omitempty"`
+	Remark              string `json:"remark,omitempty"`
+
+	// Category is assigned after the scan by the category rules
+	Category string `json:"category,omitempty"`
+
+	// Sources tells where the transaction is printed in the PDF
+	Sources Sources `json:"sources,omitempty"`
+
+	// Confidence is between 0 and 1, it drops with every issue and
+	// warning about the transaction
+	Confidence float64 `json:"confidence,omitempty"`
 }
 
+// channels a transaction went through, read from the description
+const (
+	ChannelBIFast    = "BI-FAST"
+	ChannelSwitching = "SWITCHING"
+	ChannelQRIS      = "QRIS"
+	ChannelATM       = "ATM"
+	ChannelEBanking  = "E-BANKING"
+)
+
 type ScanInfo struct {
-	Bank     string `json:"bank"`
-	Produk   string `json:"produk"`
-	Rekening string `json:"rekening"`
-	Periode  string `json:"periode"`
+	Bank      string     `json:"bank"`
+	Produk    string     `json:"produk"`
+	Rekening  string     `json:"rekening"`
+	Periode   string     `json:"periode"`
+	Detection *Detection `json:"detection,omitempty"`
+
+	PeriodStart *time.Time `json:"period_start,omitempty"`
+	PeriodEnd   *time.Time `json:"period_end,omitempty"`
+	HolderName  string     `json:"holder_name,omitempty"`
+	Branch      string     `json:"branch,omitempty"`
+	Currency    string     `json:"currency,omitempty"`
+	AccountType string     `json:"account_type,omitempty"`
+
+	// Summary holds the totals printed by the bank at the end of the
+	// statement, nil when the scanner couldn't find them.
+	Summary *StatementSummary `json:"summary,omitempty"`
+
+	Library         string           `json:"library"`
+	LibraryAttempts []LibraryAttempt `json:"library_attempts,omitempty"`
+}
+
+// Detection is filled when the bank was not given by the caller and had
+// to be guessed from the document, Score is between 0 and 1.
+type Detection struct {
+	Bank  string  `json:"bank"`
+	Score float64 `json:"score"`
+}
+
+// StatementSummary is the summary block printed after the last
+// transaction, a count of zero means the bank doesn't print it.
+type StatementSummary struct {
+	OpeningBalance money.Money `json:"opening_balance"`
+	TotalCredit    money.Money `json:"total_credit"`
+	CreditCount    int         `json:"credit_count,omitempty"`
+	TotalDebit     money.Money `json:"total_debit"`
+	DebitCount     int         `json:"debit_count,omitempty"`
+	ClosingBalance money.Money `json:"closing_balance"`
+}
+
+// LibraryAttempt records how a PDF library performed when the library is
+// picked automatically, Reason tells why it was rejected.
+type LibraryAttempt struct {
+	Library      string  `json:"library"`
+	Selected     bool    `json:"selected"`
+	Transactions int     `json:"transactions"`
+	Reconciled   float64 `json:"reconciled"`
+	Reason       string  `json:"reason,omitempty"`
 }
 
 type ScanResult struct {
 	Info         ScanInfo       `json:"info"`
 	Transactions []*Transaction `json:"transactions"`
 	Metadata     *pdf.Metadata  `json:"metadata"`
+
+	// Integrity is the fraction of transactions whose running balance
+	// reconciles, Issues lists the ones that don't.
+	Integrity float64 `json:"integrity"`
+	Issues    []Issue `json:"issues"`
+
+	// Warnings are collected while reading the statement, Confidence is
+	// the lowest confidence of the transactions so a low value means at
+	// least one of them needs a manual review.
+	Warnings   Warnings `json:"warnings"`
+	Confidence float64  `json:"confidence"`
+}
+
+const (
+	IssueBalanceMismatch  = "balance_mismatch"
+	IssueUnknownDirection = "unknown_direction"
+	IssueSummaryMismatch  = "summary_mismatch"
+)
+
+// Issue is a problem found while validating a scan result, Row is the index
+// of the transaction in ScanResult.Transactions or -1 when the issue is
+// about the whole statement.
+type Issue struct {
+	Row      int         `json:"row"`
+	Code     string      `json:"code"`
+	Message  string      `json:"message"`
+	Expected money.Money `json:"expected,omitempty"`
+	Actual   money.Money `json:"actual,omitempty"`
+}
+
+// BankScanner is implemented by every bank package and registered
+// into the EStatementScanner so the core never has to know about a
+// specific bank layout.
+type BankScanner interface {
+	ID() string
+	Name() string
+	Products() []string
+	Scan(pdfReader pdf.PDFReader) (*ScanResult, error)
+
+	// Detect scores how likely the document belongs to this bank based
+	// on the rows of the first page and the document metadata.
+	Detect(firstPage pdftypes.Rows, metadata *pdf.Metadata) float64
+}
+
+type BankInfo struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Products []string `json:"products"`
 }
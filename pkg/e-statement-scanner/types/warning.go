@@ -0,0 +1,119 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	pdftypes "github.com/mrrizkin/omniscan/pkg/pdf/types"
+)
+
+const (
+	// WarningSkippedRows is a page where the table header wasn't found,
+	// its rows are not read.
+	WarningSkippedRows = "skipped_rows"
+	// WarningUnparsedRow is a row of the table that doesn't belong to any
+	// transaction.
+	WarningUnparsedRow = "unparsed_row"
+	// WarningMissingAmount is a transaction without an amount.
+	WarningMissingAmount = "missing_amount"
+	// WarningMissingDirection is an amount that is neither debit nor credit.
+	WarningMissingDirection = "missing_direction"
+	// WarningMissingDate is a transaction without a date.
+	WarningMissingDate = "missing_date"
+	// WarningDateOutOfPeriod is a transaction dated outside the statement
+	// period.
+	WarningDateOutOfPeriod = "date_out_of_period"
+	// WarningUnparsedAmount is a word in an amount or balance column that
+	// looks like an amount but can't be read as one.
+	WarningUnparsedAmount = "unparsed_amount"
+	// WarningUnparsedDate is a word in the date column that looks like a
+	// date but can't be read as one.
+	WarningUnparsedDate = "unparsed_date"
+)
+
+var dateLikeRegex = regexp.MustCompile(`^\d{1,4}[/.-]`)
+
+// WarnFunc records a warning about the row being read, the caller knows
+// the page and the words of the row.
+type WarnFunc func(code, message string)
+
+// Warning is something the scanner read but isn't sure about, unlike an
+// Issue it doesn't mean the result is wrong. Row is the index of the
+// transaction in ScanResult.Transactions or -1 when the warning is about a
+// row of the PDF, Text is that row.
+type Warning struct {
+	Row     int    `json:"row"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Page    int    `json:"page,omitempty"`
+	Text    string `json:"text,omitempty"`
+}
+
+type Warnings []Warning
+
+// AddRow records a warning about a row of page that isn't tied to a
+// transaction.
+func (w Warnings) AddRow(code string, page int, words pdftypes.TextHorizontal, message string) Warnings {
+	text := make([]string, len(words))
+	for i, word := range words {
+		text[i] = word.S
+	}
+
+	return append(w, Warning{
+		Row:     -1,
+		Code:    code,
+		Message: message,
+		Page:    page,
+		Text:    strings.Join(text, " "),
+	})
+}
+
+// LooksLikeAmount tells whether a word of an amount column was meant to
+// be an amount, unlike markers such as "DB" or "-" it holds a digit.
+func LooksLikeAmount(s string) bool {
+	return strings.ContainsAny(s, "0123456789")
+}
+
+// LooksLikeDate tells whether a word of the date column was meant to be a
+// date, e.g. "31/02" or "2024-13-01".
+func LooksLikeDate(s string) bool {
+	return dateLikeRegex.MatchString(s)
+}
+
+// Scan reads the warnings stored as JSON text.
+func (w *Warnings) Scan(value interface{}) error {
+	var b []byte
+	switch v := value.(type) {
+	case nil:
+		*w = nil
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return fmt.Errorf("warnings: unsupported scan type %T", value)
+	}
+
+	if len(b) == 0 {
+		*w = nil
+		return nil
+	}
+	return json.Unmarshal(b, w)
+}
+
+// Value stores the warnings as JSON text.
+func (w Warnings) Value() (driver.Value, error) {
+	if len(w) == 0 {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(w)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,97 @@
+package types
+
+import "time"
+
+// the year used when the statement doesn't tell any
+const fallbackYear = 1900
+
+// YearResolver picks the year of dates printed without one, e.g. "31/12".
+// A date is placed inside the statement period when possible, otherwise in
+// the year closest to the previous row so that December followed by January
+// rolls over to the next year. Year is used for the first row when the
+// period is unknown.
+type YearResolver struct {
+	Start time.Time
+	End   time.Time
+	Year  int
+
+	last time.Time
+}
+
+// Resolve moves date, parsed from a layout without a year, into the
+// inferred year and remembers it for the next rows. A 29 February is only
+// placed in leap years.
+func (r *YearResolver) Resolve(date time.Time) time.Time {
+	reference := r.last
+	if reference.IsZero() {
+		reference = r.Start
+	}
+	if reference.IsZero() {
+		year := r.Year
+		if year == 0 {
+			year = fallbackYear
+		}
+		reference = time.Date(year, date.Month(), 1, 0, 0, 0, 0, date.Location())
+	}
+
+	// leap years are at most 8 years apart, e.g. 1896 and 1904
+	var best time.Time
+	bestInPeriod := false
+	for year := reference.Year() - 4; year <= reference.Year()+4; year++ {
+		candidate, ok := withYear(date, year)
+		if !ok {
+			continue
+		}
+
+		inPeriod := r.inPeriod(candidate)
+		if best.IsZero() ||
+			(inPeriod && !bestInPeriod) ||
+			(inPeriod == bestInPeriod && distance(candidate, reference) < distance(best, reference)) {
+			best, bestInPeriod = candidate, inPeriod
+		}
+	}
+
+	r.last = best
+	return best
+}
+
+// Period returns the statement period, nil when it is unknown.
+func (r *YearResolver) Period() (start, end *time.Time) {
+	if !r.Start.IsZero() {
+		start = &r.Start
+	}
+	if !r.End.IsZero() {
+		end = &r.End
+	}
+	return
+}
+
+func (r *YearResolver) inPeriod(date time.Time) bool {
+	if r.Start.IsZero() || r.End.IsZero() {
+		return false
+	}
+	return !date.Before(r.Start) && date.Before(r.End.AddDate(0, 0, 1))
+}
+
+// withYear moves date into year, ok is false when the day doesn't exist
+// in that year
+func withYear(date time.Time, year int) (time.Time, bool) {
+	moved := time.Date(
+		year,
+		date.Month(),
+		date.Day(),
+		date.Hour(),
+		date.Minute(),
+		date.Second(),
+		date.Nanosecond(),
+		date.Location(),
+	)
+	return moved, moved.Month() == date.Month()
+}
+
+func distance(a, b time.Time) time.Duration {
+	if a.After(b) {
+		return a.Sub(b)
+	}
+	return b.Sub(a)
+}
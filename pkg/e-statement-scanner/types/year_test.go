@@ -0,0 +1,103 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestYearResolverResolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		resolver YearResolver
+		dates    []string
+		want     []time.Time
+	}{
+		{
+			name:     "period crossing the year",
+			resolver: YearResolver{Start: day(2024, 12, 15), End: day(2025, 1, 14)},
+			dates:    []string{"15/12", "31/12", "01/01", "14/01"},
+			want:     []time.Time{day(2024, 12, 15), day(2024, 12, 31), day(2025, 1, 1), day(2025, 1, 14)},
+		},
+		{
+			name:     "year without a period rolls over",
+			resolver: YearResolver{Year: 2024},
+			dates:    []string{"30/12", "02/01", "15/01"},
+			want:     []time.Time{day(2024, 12, 30), day(2025, 1, 2), day(2025, 1, 15)},
+		},
+		{
+			name:     "no period and no year",
+			resolver: YearResolver{},
+			dates:    []string{"30/12", "02/01"},
+			want:     []time.Time{day(1900, 12, 30), day(1901, 1, 2)},
+		},
+		{
+			name:     "row printed before the period",
+			resolver: YearResolver{Start: day(2025, 1, 1), End: day(2025, 1, 31)},
+			dates:    []string{"31/12", "02/01"},
+			want:     []time.Time{day(2024, 12, 31), day(2025, 1, 2)},
+		},
+		{
+			name:     "leap day in the period",
+			resolver: YearResolver{Start: day(2024, 2, 1), End: day(2024, 2, 29)},
+			dates:    []string{"28/02", "29/02"},
+			want:     []time.Time{day(2024, 2, 28), day(2024, 2, 29)},
+		},
+		{
+			name:     "leap day with a non leap period",
+			resolver: YearResolver{Start: day(2023, 2, 1), End: day(2023, 3, 31)},
+			dates:    []string{"29/02"},
+			want:     []time.Time{day(2024, 2, 29)},
+		},
+		{
+			name:     "leap day with a non leap year",
+			resolver: YearResolver{Year: 2023},
+			dates:    []string{"29/02"},
+			want:     []time.Time{day(2024, 2, 29)},
+		},
+		{
+			name:     "leap day with the fallback year",
+			resolver: YearResolver{},
+			dates:    []string{"29/02"},
+			want:     []time.Time{day(1896, 2, 29)},
+		},
+		{
+			name:     "leap day after the previous row",
+			resolver: YearResolver{},
+			dates:    []string{"15/02", "29/02", "01/03"},
+			want:     []time.Time{day(1900, 2, 15), day(1896, 2, 29), day(1896, 3, 1)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := tt.resolver
+			for i, s := range tt.dates {
+				parsed, err := time.Parse("02/01", s)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if got := resolver.Resolve(parsed); !got.Equal(tt.want[i]) {
+					t.Errorf("Resolve(%s) = %s, want %s", s, got.Format(time.DateOnly), tt.want[i].Format(time.DateOnly))
+				}
+			}
+		})
+	}
+}
+
+func TestYearResolverPeriod(t *testing.T) {
+	start, end := new(YearResolver).Period()
+	if start != nil || end != nil {
+		t.Errorf("Period() = %v, %v, want nil, nil", start, end)
+	}
+
+	resolver := YearResolver{Start: day(2024, 12, 15), End: day(2025, 1, 14)}
+	start, end = resolver.Period()
+	if start == nil || !start.Equal(resolver.Start) || end == nil || !end.Equal(resolver.End) {
+		t.Errorf("Period() = %v, %v, want the start and the end", start, end)
+	}
+}
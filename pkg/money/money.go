@@ -0,0 +1,163 @@
+package money
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Money is an amount in hundredths of the currency unit, statements print
+// amounts with two decimals so every printed amount is represented exactly.
+type Money int64
+
+const scale = 100
+
+// Parse reads a plain decimal amount such as "-1234567.89", thousand
+// separators must be removed by the caller as they differ between banks.
+// Digits after the second decimal are rounded half away from zero.
+func Parse(s string) (Money, error) {
+	input := s
+	s = strings.TrimSpace(s)
+
+	negative := false
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		negative = s[0] == '-'
+		s = s[1:]
+	}
+
+	whole, fraction, _ := strings.Cut(s, ".")
+	if (whole == "" && fraction == "") || !isDigits(whole) || !isDigits(fraction) {
+		return 0, fmt.Errorf("money: invalid amount %q", input)
+	}
+
+	units := int64(0)
+	if whole != "" {
+		var err error
+		units, err = strconv.ParseInt(whole, 10, 64)
+		if err != nil || units > (math.MaxInt64-scale)/scale {
+			return 0, fmt.Errorf("money: amount %q out of range", input)
+		}
+	}
+
+	cents := int64(0)
+	for i := 0; i < 2; i++ {
+		cents = cents * 10
+		if i < len(fraction) {
+			cents += int64(fraction[i] - '0')
+		}
+	}
+	if len(fraction) > 2 && fraction[2] >= '5' {
+		cents++
+	}
+
+	amount := Money(units*scale + cents)
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+// FromFloat converts a float amount, e.g. an aggregate computed by the
+// database, rounding it to the nearest cent.
+func FromFloat(f float64) Money {
+	return Money(math.Round(f * scale))
+}
+
+// FromInt converts a whole amount without decimals.
+func FromInt(i int64) Money {
+	return Money(i * scale)
+}
+
+func (m Money) Float64() float64 {
+	return float64(m) / scale
+}
+
+func (m Money) Abs() Money {
+	if m < 0 {
+		return -m
+	}
+	return m
+}
+
+// String formats the amount with two decimals and no thousand separator.
+func (m Money) String() string {
+	sign := ""
+	abs := uint64(m)
+	if m < 0 {
+		sign = "-"
+		abs = uint64(-(m + 1)) + 1
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, abs/scale, abs%scale)
+}
+
+// MarshalJSON writes the amount as a JSON number with exactly two decimals
+// so no precision is lost on the way out.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON accepts both a JSON number and a quoted amount.
+func (m *Money) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	if s == "null" {
+		return nil
+	}
+
+	amount, err := Parse(strings.Trim(s, `"`))
+	if err != nil {
+		return err
+	}
+	*m = amount
+	return nil
+}
+
+// Scan reads a numeric column, drivers return it as text, float or
+// integer depending on the database.
+func (m *Money) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*m = 0
+	case int64:
+		*m = FromInt(v)
+	case float64:
+		*m = FromFloat(v)
+	case []byte:
+		return m.scanString(string(v))
+	case string:
+		return m.scanString(v)
+	default:
+		return fmt.Errorf("money: unsupported scan type %T", value)
+	}
+	return nil
+}
+
+func (m *Money) scanString(s string) error {
+	amount, err := Parse(s)
+	if err != nil {
+		// sqlite may return a float written in exponent notation
+		f, floatErr := strconv.ParseFloat(s, 64)
+		if floatErr != nil {
+			return err
+		}
+		amount = FromFloat(f)
+	}
+	*m = amount
+	return nil
+}
+
+// Value writes the amount as decimal text so the numeric column stores it
+// without going through a float.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
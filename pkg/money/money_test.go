@@ -0,0 +1,179 @@
+package money
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    Money
+		wantErr bool
+	}{
+		{name: "whole", s: "1250000", want: 125000000},
+		{name: "two decimals", s: "1234567.89", want: 123456789},
+		{name: "one decimal", s: "0.5", want: 50},
+		{name: "no whole part", s: ".5", want: 50},
+		{name: "trailing point", s: "5.", want: 500},
+		{name: "spaces around", s: " 12.30 ", want: 1230},
+		{name: "negative", s: "-1234.56", want: -123456},
+		{name: "plus sign", s: "+12", want: 1200},
+		{name: "negative zero", s: "-0.00", want: 0},
+		{name: "round down", s: "1.004", want: 100},
+		{name: "round half up", s: "1.005", want: 101},
+		{name: "round half away from zero", s: "-1.005", want: -101},
+		{name: "round up into the whole", s: "1.999", want: 200},
+		{name: "only the third decimal rounds", s: "1.0049", want: 100},
+		{name: "largest amount", s: "92233720368547757.99", want: 9223372036854775799},
+		{name: "out of range", s: "92233720368547758", wantErr: true},
+		{name: "overflowing int64", s: "99999999999999999999", wantErr: true},
+		{name: "empty", s: "", wantErr: true},
+		{name: "sign only", s: "-", wantErr: true},
+		{name: "point only", s: ".", wantErr: true},
+		{name: "thousand separator", s: "1,000.00", wantErr: true},
+		{name: "exponent", s: "1e5", wantErr: true},
+		{name: "two signs", s: "--5", wantErr: true},
+		{name: "text", s: "DB", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%q) error = %v, wantErr %v", tt.s, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Parse(%q) = %d, want %d", tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		m    Money
+		want string
+	}{
+		{m: 0, want: "0.00"},
+		{m: 5, want: "0.05"},
+		{m: 123456789, want: "1234567.89"},
+		{m: -5, want: "-0.05"},
+		{m: -123456, want: "-1234.56"},
+		{m: math.MinInt64, want: "-92233720368547758.08"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.m.String(); got != tt.want {
+				t.Errorf("String() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScan(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    Money
+		wantErr bool
+	}{
+		{name: "nil", value: nil, want: 0},
+		{name: "int64", value: int64(1250000), want: 125000000},
+		{name: "float64", value: 1234.56, want: 123456},
+		{name: "float64 rounded", value: 0.1 + 0.2, want: 30},
+		{name: "bytes", value: []byte("1234.56"), want: 123456},
+		{name: "string", value: "-75000.00", want: -7500000},
+		{name: "exponent", value: "1.5e+06", want: 150000000},
+		{name: "exponent bytes", value: []byte("1.23456E3"), want: 123456},
+		{name: "invalid text", value: "abc", wantErr: true},
+		{name: "unsupported type", value: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := Money(-1)
+			err := m.Scan(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Scan(%v) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !tt.wantErr && m != tt.want {
+				t.Errorf("Scan(%v) = %d, want %d", tt.value, m, tt.want)
+			}
+		})
+	}
+}
+
+func TestValue(t *testing.T) {
+	value, err := Money(-123456).Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != "-1234.56" {
+		t.Errorf("Value() = %v, want -1234.56", value)
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	b, err := json.Marshal(struct {
+		Change  Money `json:"change"`
+		Balance Money `json:"balance"`
+	}{Change: 5, Balance: -123456789})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"change":0.05,"balance":-1234567.89}`; string(b) != want {
+		t.Errorf("Marshal() = %s, want %s", b, want)
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		want    Money
+		wantErr bool
+	}{
+		{name: "number", json: `1234.56`, want: 123456},
+		{name: "whole number", json: `2500000`, want: 250000000},
+		{name: "negative number", json: `-0.05`, want: -5},
+		{name: "quoted", json: `"1234.56"`, want: 123456},
+		{name: "null keeps the amount", json: `null`, want: 42},
+		{name: "exponent", json: `1e3`, wantErr: true},
+		{name: "text", json: `"abc"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := Money(42)
+			err := json.Unmarshal([]byte(tt.json), &m)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal(%s) error = %v, wantErr %v", tt.json, err, tt.wantErr)
+			}
+			if !tt.wantErr && m != tt.want {
+				t.Errorf("Unmarshal(%s) = %d, want %d", tt.json, m, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromFloat(t *testing.T) {
+	tests := []struct {
+		f    float64
+		want Money
+	}{
+		{f: 0, want: 0},
+		{f: 1234.56, want: 123456},
+		{f: 0.1 + 0.2, want: 30},
+		{f: -0.015, want: -2},
+	}
+
+	for _, tt := range tests {
+		if got := FromFloat(tt.f); got != tt.want {
+			t.Errorf("FromFloat(%v) = %d, want %d", tt.f, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package money
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+)
+
+var currencyRegex = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// Rates converts amounts into the base currency, a rate is the value of one
+// unit of the currency in the base currency, e.g. {"USD": 15850}.
+type Rates struct {
+	Base  string
+	rates map[string]float64
+}
+
+// LoadRates reads the rate table from a JSON file, an empty filename gives
+// a table that only knows the base currency.
+func LoadRates(base, filename string) (*Rates, error) {
+	rates := &Rates{Base: base, rates: make(map[string]float64)}
+	if filename == "" {
+		return rates, nil
+	}
+
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(b, &rates.rates); err != nil {
+		return nil, fmt.Errorf("invalid rate file %s: %w", filename, err)
+	}
+
+	for currency, rate := range rates.rates {
+		if !currencyRegex.MatchString(currency) {
+			return nil, fmt.Errorf("invalid currency %q in %s", currency, filename)
+		}
+		if rate <= 0 {
+			return nil, fmt.Errorf("invalid rate for %s in %s", currency, filename)
+		}
+	}
+
+	return rates, nil
+}
+
+// Rate returns the value of one unit of currency in the base currency.
+func (r *Rates) Rate(currency string) (float64, bool) {
+	if currency == r.Base {
+		return 1, true
+	}
+	rate, ok := r.rates[currency]
+	return rate, ok
+}
+
+// Covers tells whether every currency can be converted.
+func (r *Rates) Covers(currencies []string) bool {
+	for _, currency := range currencies {
+		if _, ok := r.Rate(currency); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Currencies lists the currencies with a rate, sorted.
+func (r *Rates) Currencies() []string {
+	currencies := make([]string, 0, len(r.rates))
+	for currency := range r.rates {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	return currencies
+}
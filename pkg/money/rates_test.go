@@ -0,0 +1,93 @@
+package money
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeRates(t *testing.T, content string) string {
+	t.Helper()
+
+	filename := filepath.Join(t.TempDir(), "rates.json")
+	if err := os.WriteFile(filename, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func TestLoadRates(t *testing.T) {
+	rates, err := LoadRates("IDR", writeRates(t, `{"USD": 15850, "SGD": 11800.5}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		currency string
+		want     float64
+		wantOk   bool
+	}{
+		{currency: "IDR", want: 1, wantOk: true},
+		{currency: "USD", want: 15850, wantOk: true},
+		{currency: "SGD", want: 11800.5, wantOk: true},
+		{currency: "EUR"},
+	}
+	for _, tt := range tests {
+		got, ok := rates.Rate(tt.currency)
+		if got != tt.want || ok != tt.wantOk {
+			t.Errorf("Rate(%s) = %v, %v, want %v, %v", tt.currency, got, ok, tt.want, tt.wantOk)
+		}
+	}
+
+	if !rates.Covers([]string{"IDR", "USD"}) {
+		t.Error("Covers(IDR, USD) = false, want true")
+	}
+	if rates.Covers([]string{"USD", "EUR"}) {
+		t.Error("Covers(USD, EUR) = true, want false")
+	}
+	if got, want := rates.Currencies(), []string{"SGD", "USD"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Currencies() = %v, want %v", got, want)
+	}
+}
+
+func TestLoadRatesWithoutFile(t *testing.T) {
+	rates, err := LoadRates("IDR", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rates.Covers([]string{"IDR"}) || rates.Covers([]string{"USD"}) {
+		t.Error("a table without file should only cover the base currency")
+	}
+	if got := rates.Currencies(); len(got) != 0 {
+		t.Errorf("Currencies() = %v, want none", got)
+	}
+}
+
+func TestLoadRatesInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "invalid json", content: `{"USD": }`, wantErr: "invalid rate file"},
+		{name: "rate as text", content: `{"USD": "15850"}`, wantErr: "invalid rate file"},
+		{name: "lowercase currency", content: `{"usd": 15850}`, wantErr: `invalid currency "usd"`},
+		{name: "zero rate", content: `{"USD": 0}`, wantErr: "invalid rate for USD"},
+		{name: "negative rate", content: `{"USD": -1}`, wantErr: "invalid rate for USD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadRates("IDR", writeRates(t, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("LoadRates() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := LoadRates("IDR", filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("LoadRates() of a missing file should fail")
+	}
+}
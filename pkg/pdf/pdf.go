@@ -11,4 +11,8 @@ type (
 	PDFPage interface {
 		GetTextByRow(tolerance float64) (types.Rows, error)
 	}
+
+	// OpenFunc opens a PDF document with a specific provider, each
+	// provider package exposes one as Open.
+	OpenFunc func(filename string, b []byte) (PDFReader, error)
 )
@@ -0,0 +1,72 @@
+package fixture
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/mrrizkin/omniscan/pkg/pdf"
+	"github.com/mrrizkin/omniscan/pkg/pdf/types"
+)
+
+// Fixture is the text layout of a statement captured as rows, it lets a
+// scanner be run and checked without the original PDF document. Other
+// keys in the file, such as the expected transactions, are ignored.
+type (
+	Fixture struct {
+		Pages []types.Rows `json:"pages"`
+	}
+
+	Reader struct {
+		fixture Fixture
+	}
+
+	Page struct {
+		rows types.Rows
+	}
+)
+
+func NewReader(filename string, b []byte) (*Reader, error) {
+	var fixture Fixture
+	if err := json.Unmarshal(b, &fixture); err != nil {
+		return nil, fmt.Errorf("invalid fixture %s: %w", filename, err)
+	}
+
+	return &Reader{fixture: fixture}, nil
+}
+
+func Open(filename string, b []byte) (pdf.PDFReader, error) {
+	reader, err := NewReader(filename, b)
+	if err != nil {
+		return nil, err
+	}
+	return reader, nil
+}
+
+func (r *Reader) NumPage() int {
+	return len(r.fixture.Pages)
+}
+
+func (r *Reader) Page(page int) (pdf.PDFPage, error) {
+	if page < 1 || page > len(r.fixture.Pages) {
+		return nil, fmt.Errorf("page %d out of range", page)
+	}
+	return &Page{rows: r.fixture.Pages[page-1]}, nil
+}
+
+// GetTextByRow returns the rows as they are written in the fixture, the
+// tolerance is ignored as the rows are already grouped
+func (p *Page) GetTextByRow(tolerance float64) (types.Rows, error) {
+	rows := make(types.Rows, len(p.rows))
+	for i, row := range p.rows {
+		content := make(types.TextHorizontal, len(row.Content))
+		copy(content, row.Content)
+		sort.Sort(content)
+		rows[i] = &types.Row{
+			Position: row.Position,
+			Content:  content,
+		}
+	}
+	sort.Sort(rows)
+	return rows, nil
+}
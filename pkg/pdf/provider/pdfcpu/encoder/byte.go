@@ -1,6 +1,10 @@
 package encoder
 
-import "unicode"
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
 
 const NoRune = unicode.ReplacementChar
 
@@ -110,6 +114,42 @@ var (
 		0xf8ff, 0x00d2, 0x00da, 0x00db, 0x00d9, 0x0131, 0x02c6, 0x02dc,
 		0x00af, 0x02d8, 0x02d9, 0x02da, 0x00b8, 0x02dd, 0x02db, 0x02c7,
 	}
+
+	// See PDF 32000-1:2008, Table D.2
+	standardEncoding = [256]rune{
+		NoRune, NoRune, NoRune, NoRune, NoRune, NoRune, NoRune, NoRune,
+		NoRune, NoRune, NoRune, NoRune, NoRune, NoRune, NoRune, NoRune,
+		NoRune, NoRune, NoRune, NoRune, NoRune, NoRune, NoRune, NoRune,
+		NoRune, NoRune, NoRune, NoRune, NoRune, NoRune, NoRune, NoRune,
+		0x0020, 0x0021, 0x0022, 0x0023, 0x0024, 0x0025, 0x0026, 0x2019,
+		0x0028, 0x0029, 0x002a, 0x002b, 0x002c, 0x002d, 0x002e, 0x002f,
+		0x0030, 0x0031, 0x0032, 0x0033, 0x0034, 0x0035, 0x0036, 0x0037,
+		0x0038, 0x0039, 0x003a, 0x003b, 0x003c, 0x003d, 0x003e, 0x003f,
+		0x0040, 0x0041, 0x0042, 0x0043, 0x0044, 0x0045, 0x0046, 0x0047,
+		0x0048, 0x0049, 0x004a, 0x004b, 0x004c, 0x004d, 0x004e, 0x004f,
+		0x0050, 0x0051, 0x0052, 0x0053, 0x0054, 0x0055, 0x0056, 0x0057,
+		0x0058, 0x0059, 0x005a, 0x005b, 0x005c, 0x005d, 0x005e, 0x005f,
+		0x2018, 0x0061, 0x0062, 0x0063, 0x0064, 0x0065, 0x0066, 0x0067,
+		0x0068, 0x0069, 0x006a, 0x006b, 0x006c, 0x006d, 0x006e, 0x006f,
+		0x0070, 0x0071, 0x0072, 0x0073, 0x0074, 0x0075, 0x0076, 0x0077,
+		0x0078, 0x0079, 0x007a, 0x007b, 0x007c, 0x007d, 0x007e, NoRune,
+		NoRune, NoRune, NoRune, NoRune, NoRune, NoRune, NoRune, NoRune,
+		NoRune, NoRune, NoRune, NoRune, NoRune, NoRune, NoRune, NoRune,
+		NoRune, NoRune, NoRune, NoRune, NoRune, NoRune, NoRune, NoRune,
+		NoRune, NoRune, NoRune, NoRune, NoRune, NoRune, NoRune, NoRune,
+		NoRune, 0x00a1, 0x00a2, 0x00a3, 0x2044, 0x00a5, 0x0192, 0x00a7,
+		0x00a4, 0x0027, 0x201c, 0x00ab, 0x2039, 0x203a, 0xfb01, 0xfb02,
+		NoRune, 0x2013, 0x2020, 0x2021, 0x00b7, NoRune, 0x00b6, 0x2022,
+		0x201a, 0x201e, 0x201d, 0x00bb, 0x2026, 0x2030, NoRune, 0x00bf,
+		NoRune, 0x0060, 0x00b4, 0x02c6, 0x02dc, 0x00af, 0x02d8, 0x02d9,
+		0x00a8, NoRune, 0x02da, 0x00b8, NoRune, 0x02dd, 0x02db, 0x02c7,
+		0x2014, NoRune, NoRune, NoRune, NoRune, NoRune, NoRune, NoRune,
+		NoRune, NoRune, NoRune, NoRune, NoRune, NoRune, NoRune, NoRune,
+		NoRune, 0x00c6, NoRune, 0x00aa, NoRune, NoRune, NoRune, NoRune,
+		0x0141, 0x00d8, 0x0152, 0x00ba, NoRune, NoRune, NoRune, NoRune,
+		NoRune, 0x00e6, NoRune, NoRune, NoRune, 0x0131, NoRune, NoRune,
+		0x0142, 0x00f8, 0x0153, 0x00df, NoRune, NoRune, NoRune, NoRune,
+	}
 )
 
 type ByteEncoder struct {
@@ -135,3 +175,67 @@ func NewPDFDocEncoding() *ByteEncoder {
 func NewMacRomanEncoding() *ByteEncoder {
 	return &ByteEncoder{table: &macRomanEncoding}
 }
+
+func NewStandardEncoding() *ByteEncoder {
+	return &ByteEncoder{table: &standardEncoding}
+}
+
+// NewByteEncoding returns the predefined encoding of a simple font by its
+// name, nil when the name isn't known
+func NewByteEncoding(name string) *ByteEncoder {
+	switch name {
+	case "WinAnsiEncoding":
+		return NewWinAnsiEncoding()
+	case "MacRomanEncoding":
+		return NewMacRomanEncoding()
+	case "StandardEncoding":
+		return NewStandardEncoding()
+	case "PDFDocEncoding":
+		return NewPDFDocEncoding()
+	}
+	return nil
+}
+
+// WithDifferences returns a copy of the encoding with the codes of an
+// /Differences array mapped to their glyph names, a name that isn't in the
+// Adobe glyph list decodes to NoRune
+func (e *ByteEncoder) WithDifferences(differences map[byte]string) *ByteEncoder {
+	table := *e.table
+	for code, name := range differences {
+		r, ok := GlyphRune(name)
+		if !ok {
+			r = NoRune
+		}
+		table[code] = r
+	}
+	return &ByteEncoder{table: &table}
+}
+
+// GlyphRune returns the character of a glyph name, the names missing from
+// the Adobe glyph list may be written as uniXXXX or uXXXX and may have a
+// suffix such as ".sc"
+func GlyphRune(name string) (rune, bool) {
+	if i := strings.IndexByte(name, '.'); i > 0 {
+		name = name[:i]
+	}
+
+	if r, ok := nameToRune[name]; ok {
+		return r, true
+	}
+
+	var hex string
+	switch {
+	case strings.HasPrefix(name, "uni") && len(name) == 7:
+		hex = name[3:]
+	case strings.HasPrefix(name, "u") && len(name) >= 5 && len(name) <= 7:
+		hex = name[1:]
+	default:
+		return 0, false
+	}
+
+	code, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || code > unicode.MaxRune {
+		return 0, false
+	}
+	return rune(code), true
+}
@@ -6,7 +6,9 @@ import (
 	"errors"
 	"io"
 	"regexp"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/mrrizkin/omniscan/pkg/pdf/utils"
 )
@@ -22,8 +24,8 @@ var (
 
 type (
 	byteRange struct {
-		low  string
-		high string
+		low  uint32
+		high uint32
 	}
 
 	bfChar struct {
@@ -31,81 +33,227 @@ type (
 		repl string
 	}
 
+	// bfRange maps the codes of n bytes from lo to hi, dst is the UTF-16BE
+	// text of lo and the next codes increment its last byte
 	bfRange struct {
-		lo  string
-		hi  string
-		dst interface{}
+		n   int
+		lo  uint32
+		hi  uint32
+		dst string
+
+		// order is the position of the range in the declarations, the
+		// first declared range of a code wins
+		order int
+
+		// reach is the highest hi up to this range once sorted, a lookup
+		// walks back while it covers the code
+		reach uint32
 	}
 
+	// rangeChar is a code of a bfrange decoded ahead
+	rangeChar struct {
+		text  string
+		order int
+	}
+
+	// CMap maps the codes of a font to text. The mappings are indexed by code
+	// length once parsed: the bfchar ones and the codes of the ranges spanning
+	// less than maxExpandedRange codes are decoded in maps, the other ranges
+	// are sorted by their first code.
+	//
+	// A code mapped more than once decodes as the first bfchar mapping it,
+	// or else as the first declared bfrange covering it, whether that range
+	// was decoded ahead or not.
 	CMap struct {
-		space   [4][]byteRange
-		bfchar  []bfChar
-		bfrange []bfRange
+		space      [4][]byteRange
+		chars      [4]map[uint32]string
+		rangeChars [4]map[uint32]rangeChar
+		ranges     [4][]bfRange
+
+		// nranges is the number of declared ranges, the order of the next
+		// merged ones starts after it
+		nranges int
 	}
 )
 
+// maxExpandedRange bounds the codes of a bfrange decoded ahead, the ranges
+// of a valid CMap only vary in their last byte
+const maxExpandedRange = 256
+
+// Merge adds the mappings of other as if they were declared after the
+// ones of cm, a bfchar of other still wins over a bfrange of cm
 func (cm *CMap) Merge(other *CMap) {
-	cm.bfchar = append(cm.bfchar, other.bfchar...)
-	cm.bfrange = append(cm.bfrange, other.bfrange...)
+	offset := cm.nranges
 	for i := 0; i < 4; i++ {
 		cm.space[i] = append(cm.space[i], other.space[i]...)
+
+		for code, text := range other.chars[i] {
+			if cm.chars[i] == nil {
+				cm.chars[i] = make(map[uint32]string, len(other.chars[i]))
+			}
+			if _, ok := cm.chars[i][code]; !ok {
+				cm.chars[i][code] = text
+			}
+		}
+
+		for code, char := range other.rangeChars[i] {
+			if cm.rangeChars[i] == nil {
+				cm.rangeChars[i] = make(map[uint32]rangeChar, len(other.rangeChars[i]))
+			}
+			if _, ok := cm.rangeChars[i][code]; !ok {
+				cm.rangeChars[i][code] = rangeChar{char.text, char.order + offset}
+			}
+		}
+
+		for _, bfrange := range other.ranges[i] {
+			bfrange.order += offset
+			cm.ranges[i] = append(cm.ranges[i], bfrange)
+		}
+		sortRanges(cm.ranges[i])
 	}
+	cm.nranges += other.nranges
 }
 
 func (cm *CMap) Decode(raw string) string {
-	var r []rune
+	var sb strings.Builder
+	sb.Grow(len(raw))
 
 Decode:
 	for len(raw) > 0 {
-		for n := 1; n <= 4 && n <= len(raw); n++ { // number of digits in character replacement (1-4 possible)
-			for _, space := range cm.space[n-1] { // find matching codespace Ranges for number of digits
-				if space.low <= raw[:n] && raw[:n] <= space.high { // see if value is in range
-					text := raw[:n]
-					raw = raw[n:]
-					for _, bfchar := range cm.bfchar { // check for matching bfchar
-						if len(bfchar.orig) == n && bfchar.orig == text {
-							r = append(r, []rune(Utf16Decode(bfchar.repl))...)
-							continue Decode
-						}
-					}
-					for _, bfrange := range cm.bfrange { // check for matching bfrange
-						if len(bfrange.lo) == n && bfrange.lo <= text && text <= bfrange.hi {
-							if dst, ok := bfrange.dst.(string); ok {
-								s := dst
-								if bfrange.lo != text { // value isn't at the beginning of the range so scale result
-									b := []byte(s)
-									b[len(b)-1] += text[len(text)-1] - bfrange.lo[len(bfrange.lo)-1] // increment last byte by difference
-									s = string(b)
-								}
-								r = append(r, []rune(Utf16Decode(s))...)
-								continue Decode
-							}
-							if sarray, ok := bfrange.dst.([]string); ok {
-								n := text[len(text)-1] - bfrange.lo[len(bfrange.lo)-1]
-								v := sarray[n]
-								r = append(r, []rune(Utf16Decode(v))...)
-								continue Decode
-							}
-							r = append(r, NoRune)
-							continue Decode
-						}
-					}
-					r = append(r, NoRune)
-					continue Decode
-				}
+		for n := 1; n <= 4 && n <= len(raw); n++ { // number of bytes of the code (1-4 possible)
+			code := codeOf(raw[:n])
+			if !cm.inSpace(n, code) {
+				continue
 			}
+
+			raw = raw[n:]
+			if text, ok := cm.lookup(n, code); ok {
+				sb.WriteString(text)
+			} else {
+				sb.WriteRune(NoRune)
+			}
+			continue Decode
 		}
 
-		r = append(r, NoRune)
+		sb.WriteRune(NoRune)
 		raw = raw[1:]
 	}
 
-	// check if there's any string that non utf8
-	if !utils.IsUTF8(string(r)) {
-		return string(r)
+	return sb.String()
+}
+
+func (cm *CMap) inSpace(n int, code uint32) bool {
+	for _, space := range cm.space[n-1] {
+		if space.low <= code && code <= space.high {
+			return true
+		}
+	}
+	return false
+}
+
+// lookup returns the text of a code of n bytes, bfchar mappings come before
+// bfrange ones and the first declared bfrange covering the code wins
+func (cm *CMap) lookup(n int, code uint32) (string, bool) {
+	if text, ok := cm.chars[n-1][code]; ok {
+		return text, true
+	}
+
+	char, found := cm.rangeChars[n-1][code]
+	ranges := cm.ranges[n-1]
+	i := sort.Search(len(ranges), func(i int) bool { return ranges[i].lo > code }) - 1
+	for ; i >= 0 && ranges[i].reach >= code; i-- {
+		if code <= ranges[i].hi && (!found || ranges[i].order < char.order) {
+			char = rangeChar{ranges[i].decode(code), ranges[i].order}
+			found = true
+		}
+	}
+	return char.text, found
+}
+
+func (r *bfRange) decode(code uint32) string {
+	if code == r.lo || len(r.dst) == 0 {
+		return Utf16Decode(r.dst)
+	}
+
+	b := []byte(r.dst)
+	b[len(b)-1] += byte(code) - byte(r.lo) // increment last byte by difference
+	return Utf16Decode(string(b))
+}
+
+// index builds the lookup tables from the parsed mappings, bfranges are
+// given in their declaration order
+func (cm *CMap) index(bfchars []bfChar, bfranges []bfRange) {
+	for _, bfchar := range bfchars {
+		n := len(bfchar.orig)
+		if n < 1 || n > 4 {
+			continue
+		}
+		if cm.chars[n-1] == nil {
+			cm.chars[n-1] = make(map[uint32]string)
+		}
+
+		code := codeOf(bfchar.orig)
+		if _, ok := cm.chars[n-1][code]; !ok {
+			cm.chars[n-1][code] = Utf16Decode(bfchar.repl)
+		}
+	}
+
+	for order, bfrange := range bfranges {
+		n := bfrange.n
+		if n < 1 || n > 4 || bfrange.lo > bfrange.hi {
+			continue
+		}
+
+		bfrange.order = order
+		if bfrange.hi-bfrange.lo >= maxExpandedRange {
+			cm.ranges[n-1] = append(cm.ranges[n-1], bfrange)
+			continue
+		}
+
+		if cm.rangeChars[n-1] == nil {
+			cm.rangeChars[n-1] = make(map[uint32]rangeChar)
+		}
+		// counted from lo so a range ending at the last code stops
+		for k := uint32(0); k <= bfrange.hi-bfrange.lo; k++ {
+			code := bfrange.lo + k
+			if _, ok := cm.rangeChars[n-1][code]; !ok {
+				cm.rangeChars[n-1][code] = rangeChar{bfrange.decode(code), order}
+			}
+		}
+	}
+	cm.nranges = len(bfranges)
+
+	for n := 1; n <= 4; n++ {
+		sortRanges(cm.ranges[n-1])
 	}
+}
 
-	return string(r)
+// sortRanges sorts ranges by their first code and sets their reach
+func sortRanges(ranges []bfRange) {
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].lo < ranges[j].lo })
+
+	reach := uint32(0)
+	for i := range ranges {
+		reach = max(reach, ranges[i].hi)
+		ranges[i].reach = reach
+	}
+}
+
+func (cm *CMap) addSpace(low, high []byte) {
+	n := len(low)
+	if n < 1 || n > 4 || len(high) != n {
+		return
+	}
+	cm.space[n-1] = append(cm.space[n-1], byteRange{codeOf(string(low)), codeOf(string(high))})
+}
+
+// codeOf reads a code of up to 4 bytes as a big-endian number
+func codeOf(s string) uint32 {
+	code := uint32(0)
+	for i := 0; i < len(s); i++ {
+		code = code<<8 | uint32(s[i])
+	}
+	return code
 }
 
 type StructedCMap struct {
@@ -130,6 +278,8 @@ func ParseCmap(cmapBytes []byte) (*CMap, error) {
 	}
 
 	var currentIntParam int
+	var bfchars []bfChar
+	var bfranges []bfRange
 	var currentSection string
 
 	mapping := make(map[string]*StructedCMap)
@@ -149,7 +299,9 @@ func ParseCmap(cmapBytes []byte) (*CMap, error) {
 			switch currentSection {
 			case "endcodespacerange":
 				codespacerange, ok := mapping["codespacerange"]
-				if !ok {
+				// every section has its own count
+				delete(mapping, "codespacerange")
+				if !ok || codespacerange.MapCount == 0 || len(codespacerange.Mapping) < codespacerange.MapCount {
 					continue
 				}
 
@@ -164,11 +316,13 @@ func ParseCmap(cmapBytes []byte) (*CMap, error) {
 					low, _ := utils.Hex2Bytes(m[0])
 					high, _ := utils.Hex2Bytes(m[1])
 
-					cmap.space[len(low)-1] = append(cmap.space[len(low)-1], byteRange{string(low), string(high)})
+					cmap.addSpace(low, high)
 				}
 			case "endbfrange":
 				bfrange, ok := mapping["bfrange"]
-				if !ok {
+				// every section has its own count
+				delete(mapping, "bfrange")
+				if !ok || bfrange.MapCount == 0 || len(bfrange.Mapping) < bfrange.MapCount {
 					continue
 				}
 
@@ -189,12 +343,19 @@ func ParseCmap(cmapBytes []byte) (*CMap, error) {
 						dst, _ = utils.Hex2Bytes(m[2])
 					}
 
-					cmap.bfrange = append(cmap.bfrange, bfRange{string(lo), string(hi), string(dst)})
+					bfranges = append(bfranges, bfRange{
+						n:   len(lo),
+						lo:  codeOf(string(lo)),
+						hi:  codeOf(string(hi)),
+						dst: string(dst),
+					})
 				}
 
 			case "endbfchar":
 				bfchar, ok := mapping["bfchar"]
-				if !ok {
+				// every section has its own count
+				delete(mapping, "bfchar")
+				if !ok || bfchar.MapCount == 0 || len(bfchar.Mapping) < bfchar.MapCount {
 					continue
 				}
 
@@ -208,7 +369,7 @@ func ParseCmap(cmapBytes []byte) (*CMap, error) {
 
 					orig, _ := utils.Hex2Bytes(m[0])
 					repl, _ := utils.Hex2Bytes(m[1])
-					cmap.bfchar = append(cmap.bfchar, bfChar{string(orig), string(repl)})
+					bfchars = append(bfchars, bfChar{string(orig), string(repl)})
 				}
 			}
 		case "HEX":
@@ -244,9 +405,38 @@ func ParseCmap(cmapBytes []byte) (*CMap, error) {
 		}
 	}
 
+	cmap.inferSpace(bfchars, bfranges)
+	cmap.index(bfchars, bfranges)
+
 	return cmap, nil
 }
 
+// inferSpace fills the codespace from the length of the source codes when
+// the declared one matches none of them, e.g. a simple font declaring
+// <0000> <FFFF> for its single byte codes
+func (cm *CMap) inferSpace(bfchars []bfChar, bfranges []bfRange) {
+	lengths := make(map[int]bool)
+	for _, bfchar := range bfchars {
+		lengths[len(bfchar.orig)] = true
+	}
+	for _, bfrange := range bfranges {
+		lengths[bfrange.n] = true
+	}
+
+	for n := range lengths {
+		if n >= 1 && n <= 4 && len(cm.space[n-1]) > 0 {
+			return
+		}
+	}
+
+	for n := range lengths {
+		if n < 1 || n > 4 {
+			continue
+		}
+		cm.addSpace(make([]byte, n), bytes.Repeat([]byte{0xff}, n))
+	}
+}
+
 type Tokenizer struct {
 	scanner *bufio.Scanner
 }
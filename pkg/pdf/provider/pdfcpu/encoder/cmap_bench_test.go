@@ -0,0 +1,93 @@
+package encoder
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const (
+	largeBfchars  = 6000
+	largeBfranges = 400
+	largeRangeLen = 16
+	largeRangeLo  = 0x2000
+)
+
+// largeCMap builds a ToUnicode CMap shaped like the ones of CJK fonts
+// embedded in bank statements, 6000 bfchar and 400 bfrange mappings
+// declared in sections of 100
+func largeCMap() []byte {
+	var b bytes.Buffer
+	b.WriteString("begincmap\n1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	for s := 0; s < largeBfchars/100; s++ {
+		b.WriteString("100 beginbfchar\n")
+		for i := 0; i < 100; i++ {
+			c := s*100 + i
+			fmt.Fprintf(&b, "<%04X> <%04X>\n", c, 0x4E00+c)
+		}
+		b.WriteString("endbfchar\n")
+	}
+	for s := 0; s < largeBfranges/100; s++ {
+		b.WriteString("100 beginbfrange\n")
+		for i := 0; i < 100; i++ {
+			r := s*100 + i
+			lo := largeRangeLo + r*largeRangeLen
+			fmt.Fprintf(&b, "<%04X> <%04X> <%04X>\n", lo, lo+largeRangeLen-1, 0xAC00+r*largeRangeLen)
+		}
+		b.WriteString("endbfrange\n")
+	}
+	b.WriteString("endcmap\n")
+	return b.Bytes()
+}
+
+// largeCMapText is the text largeCMap declares for code
+func largeCMapText(code uint32) (string, bool) {
+	if code < largeBfchars {
+		return string(rune(0x4E00 + code)), true
+	}
+	if code >= largeRangeLo && code < largeRangeLo+largeBfranges*largeRangeLen {
+		return string(rune(0xAC00 + code - largeRangeLo)), true
+	}
+	return "", false
+}
+
+// statementText is the raw text of a 60 page statement, 3000 codes a page
+// spread over the mapped codes
+func statementText() []string {
+	pages := make([]string, 60)
+	for p := range pages {
+		var sb strings.Builder
+		for i := 0; i < 3000; i++ {
+			c := (p*7919 + i*104729) % (largeRangeLo + largeBfranges*largeRangeLen)
+			sb.WriteByte(byte(c >> 8))
+			sb.WriteByte(byte(c))
+		}
+		pages[p] = sb.String()
+	}
+	return pages
+}
+
+func BenchmarkParseLargeCMap(b *testing.B) {
+	src := largeCMap()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseCmap(src); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeStatement(b *testing.B) {
+	cmap, err := ParseCmap(largeCMap())
+	if err != nil {
+		b.Fatal(err)
+	}
+	pages := statementText()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, page := range pages {
+			cmap.Decode(page)
+		}
+	}
+}
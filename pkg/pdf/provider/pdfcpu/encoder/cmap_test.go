@@ -0,0 +1,123 @@
+package encoder
+
+import (
+	"encoding/hex"
+	"os"
+	"testing"
+)
+
+func TestCMapDecodeFixture(t *testing.T) {
+	b, err := os.ReadFile("testdata/tounicode.cmap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmap, err := ParseCmap(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{
+			name: "bfchar and bfrange",
+			raw:  "00360044004F0047005200030024005A0044004F0003001400110015001800130011001300130013000F00130013000300160014001200130014",
+			want: "Saldo Awal 1.250.000,00 31/01",
+		},
+		{
+			name: "ligature",
+			raw:  "003300550052" + "00B2" + "004F0048",
+			want: "Proﬁle",
+		},
+		{
+			name: "two byte ranges",
+			raw:  "2000" + "2005" + "20FF" + "2100" + "21FF",
+			want: "가갅곿관귿",
+		},
+		{
+			name: "unmapped code",
+			raw:  "0024" + "0500" + "0025",
+			want: "A" + string(NoRune) + "B",
+		},
+		{
+			name: "trailing byte",
+			raw:  "0024" + "00",
+			want: "A" + string(NoRune),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := hex.DecodeString(tt.raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := cmap.Decode(string(raw)); got != tt.want {
+				t.Errorf("Decode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCMapDecodeLarge decodes every code of the synthetic CMap of the
+// benchmarks and compares it with a walk over the declared mappings
+func TestCMapDecodeLarge(t *testing.T) {
+	src := largeCMap()
+	cmap, err := ParseCmap(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for code := 0; code <= 0xFFFF; code++ {
+		raw := string([]byte{byte(code >> 8), byte(code)})
+		want := string(NoRune)
+		if text, ok := largeCMapText(uint32(code)); ok {
+			want = text
+		}
+		if got := cmap.Decode(raw); got != want {
+			t.Fatalf("Decode(%04X) = %q, want %q", code, got, want)
+		}
+	}
+}
+
+// a code mapped more than once decodes as the first bfchar, or else as
+// the first declared bfrange, see CMap
+func TestCMapPrecedence(t *testing.T) {
+	parse := func(body string) *CMap {
+		cmap, err := ParseCmap([]byte("begincmap\n1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n" + body + "endcmap\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cmap
+	}
+
+	wideFirst := parse("2 beginbfrange\n<0000> <01FF> <4E00>\n<0010> <001F> <0041>\nendbfrange\n")
+	overlapping := parse("2 beginbfrange\n<0000> <02FF> <4E00>\n<0100> <03FF> <AC00>\nendbfrange\n")
+	charLast := parse("1 beginbfrange\n<0000> <01FF> <4E00>\nendbfrange\n1 beginbfchar\n<0010> <0058>\nendbfchar\n")
+
+	merged := parse("1 beginbfrange\n<0000> <01FF> <4E00>\nendbfrange\n")
+	merged.Merge(parse("1 beginbfrange\n<0010> <001F> <0041>\nendbfrange\n1 beginbfchar\n<0020> <0058>\nendbfchar\n"))
+
+	tests := []struct {
+		name string
+		cmap *CMap
+		code string
+		want string
+	}{
+		{name: "wide range declared first", cmap: wideFirst, code: "\x00\x10", want: "丐"},
+		{name: "overlapping wide ranges", cmap: overlapping, code: "\x01\x05", want: "丅"},
+		{name: "bfchar after bfrange", cmap: charLast, code: "\x00\x10", want: "X"},
+		{name: "merged range", cmap: merged, code: "\x00\x10", want: "丐"},
+		{name: "merged bfchar", cmap: merged, code: "\x00\x20", want: "X"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cmap.Decode(tt.code); got != tt.want {
+				t.Errorf("Decode(% X) = %q, want %q", tt.code, got, tt.want)
+			}
+		})
+	}
+}
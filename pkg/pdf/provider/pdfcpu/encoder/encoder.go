@@ -9,3 +9,14 @@ type NoOpEncoder struct{}
 func (e *NoOpEncoder) Decode(raw string) string {
 	return raw
 }
+
+// UTF16Encoder decodes strings of fonts using one of the predefined Unicode
+// CMaps, e.g. UniGB-UCS2-H, whose codes are UTF-16BE
+type UTF16Encoder struct{}
+
+func (e *UTF16Encoder) Decode(raw string) string {
+	if len(raw)%2 == 1 {
+		raw = raw[:len(raw)-1]
+	}
+	return Utf16Decode(raw)
+}
@@ -2,6 +2,7 @@ package pdfcpu
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/mrrizkin/omniscan/pkg/pdf/provider/pdfcpu/encoder"
@@ -20,11 +21,16 @@ type (
 		Text     string
 		FontSize float64
 		Position Position
+		Width    float64
 	}
 
 	fontObject struct {
 		*model.FontObject
 		enc encoder.TextEncoding
+
+		// composite fonts (Type0) use two bytes per character code
+		composite bool
+		widths    *glyphWidths
 	}
 
 	fonts map[string]*fontObject
@@ -37,6 +43,40 @@ func (fo *fontObject) Decode(raw string) string {
 	return raw
 }
 
+// averageGlyphWidth is used for the fonts without widths, in thousandths
+// of text space units
+const averageGlyphWidth = 500
+
+// codes splits a shown string into its character codes
+func (fo *fontObject) codes(raw string) []string {
+	size := 1
+	if fo != nil && fo.composite {
+		size = 2
+	}
+
+	codes := make([]string, 0, len(raw)/size+1)
+	for len(raw) > 0 {
+		n := min(size, len(raw))
+		codes = append(codes, raw[:n])
+		raw = raw[n:]
+	}
+	return codes
+}
+
+// glyphWidth returns the width of the glyph of code in thousandths of text
+// space units
+func (fo *fontObject) glyphWidth(code string) float64 {
+	if fo == nil || fo.widths == nil {
+		return averageGlyphWidth
+	}
+
+	c := 0
+	for i := 0; i < len(code); i++ {
+		c = c<<8 | int(code[i])
+	}
+	return fo.widths.width(c)
+}
+
 func (fs fonts) Get(resourceName string) (*fontObject, bool) {
 	resourceName = strings.TrimPrefix(resourceName, "/")
 	font, ok := fs[resourceName]
@@ -56,52 +96,91 @@ func (fo fonts) Set(resourceName string, font *fontObject) {
 	fo[resourceName] = font
 }
 
-func GetFonts(ctx *model.Context) (fonts, error) {
-	fonts := make(fonts)
-	for _, font := range ctx.Optimize.FontObjects {
-		encoding := ""
-		var enc encoder.TextEncoding
-		if encodingDict, ok := font.FontDict.Find("Encoding"); ok {
-			encoding = encodingDict.String()
+// GetFonts reads the fonts of the document by object number, a font whose
+// encoding can't be read is kept without one
+func GetFonts(ctx *model.Context) map[int]*fontObject {
+	objects := make(map[int]*fontObject, len(ctx.Optimize.FontObjects))
+	for objNr, font := range ctx.Optimize.FontObjects {
+		objects[objNr] = &fontObject{
+			FontObject: font,
+			enc:        getEncoding(ctx, font.FontName, font.FontDict),
+			composite:  isComposite(font.FontDict),
+			widths:     getWidths(ctx, font.FontName, font.FontDict),
 		}
+	}
+	return objects
+}
 
-		switch encoding {
-		case "WinAnsiEncoding":
-			enc = encoder.NewWinAnsiEncoding()
-		case "MacRomanEncoding":
-			enc = encoder.NewMacRomanEncoding()
-		case "Identity-H":
-			cmap, err := getCMap(ctx, font.FontDict)
-			if err != nil {
-				return nil, err
-			}
-
-			enc = cmap
-		}
+// documentFonts maps the resource names of every page to their fonts, the
+// CMaps of different fonts sharing a resource name are merged
+func documentFonts(objects map[int]*fontObject) fonts {
+	objNrs := make([]int, 0, len(objects))
+	for objNr := range objects {
+		objNrs = append(objNrs, objNr)
+	}
+	sort.Ints(objNrs)
 
+	fonts := make(fonts)
+	for _, objNr := range objNrs {
+		font := objects[objNr]
 		for _, resName := range font.ResourceNames {
 			fo, ok := fonts[resName]
-			if !ok {
-				fo = &fontObject{
-					FontObject: font,
-					enc:        enc,
-				}
-			} else {
-				if fo.enc == nil {
-					fo.enc = enc
-				}
-
-				if cmap, ok := enc.(*encoder.CMap); ok {
-					cmap.Merge(fo.enc.(*encoder.CMap))
-					fo.enc = cmap
-				}
+			if !ok || fo.enc == nil {
+				fonts[resName] = font
+				continue
+			}
+
+			cmap, ok := fo.enc.(*encoder.CMap)
+			other, otherOk := font.enc.(*encoder.CMap)
+			if !ok || !otherOk {
+				continue
 			}
 
-			fonts[resName] = fo
+			// the fonts are shared with the pages, merge into a copy
+			merged := *fo
+			enc := &encoder.CMap{}
+			enc.Merge(cmap)
+			enc.Merge(other)
+			merged.enc = enc
+			fonts[resName] = &merged
 		}
 	}
+	return fonts
+}
+
+// pageFonts maps the resource names of a page to their fonts, the names are
+// read from the page resources as pdfcpu only keeps the first name of a font
+func pageFonts(ctx *model.Context, objects map[int]*fontObject, page int) fonts {
+	fonts := make(fonts)
+	_, _, inherited, err := ctx.PageDict(page, false)
+	if err != nil || inherited == nil || inherited.Resources == nil {
+		return fonts
+	}
 
-	return fonts, nil
+	obj, ok := inherited.Resources.Find("Font")
+	if !ok {
+		return fonts
+	}
+	fontDict, err := ctx.DereferenceDict(obj)
+	if err != nil {
+		return fonts
+	}
+
+	for resName, obj := range fontDict {
+		ref, ok := obj.(types.IndirectRef)
+		if !ok {
+			continue
+		}
+		if font, ok := objects[ref.ObjectNumber.Value()]; ok {
+			fonts[resName] = font
+		}
+	}
+	return fonts
+}
+
+func isComposite(fontDict types.Dict) bool {
+	subtype := fontDict.Subtype()
+	return subtype != nil && *subtype == "Type0"
 }
 
 func getCMap(ctx *model.Context, fontDict types.Dict) (*encoder.CMap, error) {
@@ -115,7 +194,7 @@ func getCMap(ctx *model.Context, fontDict types.Dict) (*encoder.CMap, error) {
 		return nil, err
 	}
 
-	if !valid {
+	if !valid || stream == nil {
 		return nil, fmt.Errorf("invalid ToUnicode stream")
 	}
 
@@ -126,3 +205,131 @@ func getCMap(ctx *model.Context, fontDict types.Dict) (*encoder.CMap, error) {
 
 	return cmap, nil
 }
+
+// getEncoding reads how the codes of a font map to text, the ToUnicode CMap
+// comes first, see PDF 32000-1:2008, 9.10.2. It returns nil when the font
+// gives no way to decode its codes.
+func getEncoding(ctx *model.Context, fontName string, fontDict types.Dict) encoder.TextEncoding {
+	if cmap, err := getCMap(ctx, fontDict); err == nil {
+		return cmap
+	}
+
+	if isComposite(fontDict) {
+		return getCIDEncoding(fontDict)
+	}
+	return getSimpleEncoding(ctx, fontName, fontDict)
+}
+
+// getCIDEncoding handles the composite fonts without ToUnicode, only the
+// predefined Unicode CMaps can be decoded, Identity-H and Identity-V codes
+// are glyph ids
+func getCIDEncoding(fontDict types.Dict) encoder.TextEncoding {
+	name := fontDict.NameEntry("Encoding")
+	if name == nil {
+		return nil
+	}
+
+	if strings.HasPrefix(*name, "Uni") &&
+		(strings.Contains(*name, "-UCS2-") || strings.Contains(*name, "-UTF16-")) {
+		return &encoder.UTF16Encoder{}
+	}
+	return nil
+}
+
+// getSimpleEncoding reads /Encoding of a simple font, either the name of a
+// predefined encoding or a dictionary with /BaseEncoding and /Differences,
+// see PDF 32000-1:2008, 9.6.6
+func getSimpleEncoding(ctx *model.Context, fontName string, fontDict types.Dict) encoder.TextEncoding {
+	base := defaultEncoding(ctx, fontName, fontDict)
+
+	obj, ok := fontDict.Find("Encoding")
+	if !ok {
+		if base == nil {
+			return nil
+		}
+		return base
+	}
+
+	obj, err := ctx.Dereference(obj)
+	if err != nil {
+		return nil
+	}
+
+	switch encoding := obj.(type) {
+	case types.Name:
+		if enc := encoder.NewByteEncoding(encoding.Value()); enc != nil {
+			return enc
+		}
+		return nil
+
+	case types.Dict:
+		if name := encoding.NameEntry("BaseEncoding"); name != nil {
+			if enc := encoder.NewByteEncoding(*name); enc != nil {
+				base = enc
+			}
+		}
+		if base == nil {
+			base = encoder.NewStandardEncoding()
+		}
+		return base.WithDifferences(getDifferences(ctx, encoding))
+	}
+
+	return nil
+}
+
+// defaultEncoding is the encoding of a simple font without /Encoding,
+// StandardEncoding unless the font is symbolic and uses its own encoding
+func defaultEncoding(ctx *model.Context, fontName string, fontDict types.Dict) *encoder.ByteEncoder {
+	if fontName == "Symbol" || fontName == "ZapfDingbats" {
+		return nil
+	}
+
+	if obj, ok := fontDict.Find("FontDescriptor"); ok {
+		descriptor, err := ctx.DereferenceDict(obj)
+		if err == nil && descriptor != nil {
+			// bit 3 of /Flags, see PDF 32000-1:2008, 9.8.2
+			if flags := descriptor.IntEntry("Flags"); flags != nil && *flags&4 != 0 {
+				return nil
+			}
+		}
+	}
+
+	return encoder.NewStandardEncoding()
+}
+
+// getDifferences reads a /Differences array, a code followed by the glyph
+// names of the codes from it onwards
+func getDifferences(ctx *model.Context, encoding types.Dict) map[byte]string {
+	differences := make(map[byte]string)
+
+	obj, ok := encoding.Find("Differences")
+	if !ok {
+		return differences
+	}
+	array, err := ctx.DereferenceArray(obj)
+	if err != nil {
+		return differences
+	}
+
+	code := 0
+	for _, item := range array {
+		item, err := ctx.Dereference(item)
+		if err != nil {
+			continue
+		}
+
+		switch item := item.(type) {
+		case types.Integer:
+			code = item.Value()
+		case types.Float:
+			code = int(item.Value())
+		case types.Name:
+			if code >= 0 && code < 256 {
+				differences[byte(code)] = item.Value()
+			}
+			code++
+		}
+	}
+
+	return differences
+}
@@ -0,0 +1,293 @@
+package pdfcpu
+
+import (
+	"io"
+	"strconv"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenNumber tokenKind = iota
+	tokenString
+	tokenName
+	tokenOperator
+	tokenArrayStart
+	tokenArrayEnd
+	tokenDictStart
+	tokenDictEnd
+)
+
+// token is a lexical element of a content stream, Value holds the decoded
+// bytes of strings, the name without its slash or the operator keyword.
+type token struct {
+	Kind   tokenKind
+	Value  string
+	Number float64
+}
+
+// lexer splits a content stream into tokens, see PDF 32000-1:2008, 7.2
+// and 7.3.
+type lexer struct {
+	data []byte
+	pos  int
+}
+
+func newLexer(data []byte) *lexer {
+	return &lexer{data: data}
+}
+
+func isWhitespace(c byte) bool {
+	switch c {
+	case 0, '\t', '\n', '\f', '\r', ' ':
+		return true
+	}
+	return false
+}
+
+func isDelimiter(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+func isRegular(c byte) bool {
+	return !isWhitespace(c) && !isDelimiter(c)
+}
+
+// next returns the next token, io.EOF at the end of the stream
+func (l *lexer) next() (token, error) {
+	l.skipWhitespace()
+	if l.pos >= len(l.data) {
+		return token{}, io.EOF
+	}
+
+	c := l.data[l.pos]
+	switch {
+	case c == '(':
+		l.pos++
+		return token{Kind: tokenString, Value: l.readLiteralString()}, nil
+	case c == '<' && l.peek(1) == '<':
+		l.pos += 2
+		return token{Kind: tokenDictStart, Value: "<<"}, nil
+	case c == '>' && l.peek(1) == '>':
+		l.pos += 2
+		return token{Kind: tokenDictEnd, Value: ">>"}, nil
+	case c == '<':
+		l.pos++
+		return token{Kind: tokenString, Value: l.readHexString()}, nil
+	case c == '[':
+		l.pos++
+		return token{Kind: tokenArrayStart, Value: "["}, nil
+	case c == ']':
+		l.pos++
+		return token{Kind: tokenArrayEnd, Value: "]"}, nil
+	case c == '/':
+		l.pos++
+		return token{Kind: tokenName, Value: l.readName()}, nil
+	case isDelimiter(c):
+		// a stray ">", "{" or "}", they are not used by content streams
+		l.pos++
+		return l.next()
+	}
+
+	// a malformed number such as "1.2.3" is read as an unknown operator
+	word := l.readRegular()
+	if isNumber(word) {
+		if number, err := strconv.ParseFloat(word, 64); err == nil {
+			return token{Kind: tokenNumber, Value: word, Number: number}, nil
+		}
+	}
+	return token{Kind: tokenOperator, Value: word}, nil
+}
+
+func (l *lexer) peek(offset int) byte {
+	if l.pos+offset >= len(l.data) {
+		return 0
+	}
+	return l.data[l.pos+offset]
+}
+
+// skipWhitespace skips whitespaces and comments
+func (l *lexer) skipWhitespace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		if !isWhitespace(c) {
+			return
+		}
+		l.pos++
+	}
+}
+
+func (l *lexer) readRegular() string {
+	start := l.pos
+	for l.pos < len(l.data) && isRegular(l.data[l.pos]) {
+		l.pos++
+	}
+	return string(l.data[start:l.pos])
+}
+
+// isNumber tells integers and reals apart from operators, e.g. "-.5" or
+// "12." are numbers
+func isNumber(word string) bool {
+	digits := 0
+	for i := 0; i < len(word); i++ {
+		c := word[i]
+		switch {
+		case c >= '0' && c <= '9':
+			digits++
+		case c == '.':
+		case (c == '+' || c == '-') && i == 0:
+		default:
+			return false
+		}
+	}
+	return digits > 0
+}
+
+// readName reads a name after its slash, "#xx" is a hexadecimal escape
+func (l *lexer) readName() string {
+	word := l.readRegular()
+	if !strings.Contains(word, "#") {
+		return word
+	}
+
+	name := make([]byte, 0, len(word))
+	for i := 0; i < len(word); i++ {
+		if word[i] == '#' && i+2 < len(word) {
+			if b, err := strconv.ParseUint(word[i+1:i+3], 16, 8); err == nil {
+				name = append(name, byte(b))
+				i += 2
+				continue
+			}
+		}
+		name = append(name, word[i])
+	}
+	return string(name)
+}
+
+// readLiteralString reads a string after its opening parenthesis, balanced
+// parentheses are part of the string and the escapes are decoded
+func (l *lexer) readLiteralString() string {
+	s := make([]byte, 0)
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				return string(s)
+			}
+		case '\r':
+			// an end of line is always read as a line feed
+			if l.peek(0) == '\n' {
+				l.pos++
+			}
+			c = '\n'
+		case '\\':
+			if l.pos >= len(l.data) {
+				return string(s)
+			}
+			c = l.data[l.pos]
+			l.pos++
+			switch c {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				if l.peek(0) == '\n' {
+					l.pos++
+				}
+				continue
+			case '\n':
+				// the string continues on the next line
+				continue
+			case '0', '1', '2', '3', '4', '5', '6', '7':
+				x := int(c - '0')
+				for i := 0; i < 2 && l.pos < len(l.data); i++ {
+					d := l.data[l.pos]
+					if d < '0' || d > '7' {
+						break
+					}
+					x = x*8 + int(d-'0')
+					l.pos++
+				}
+				c = byte(x)
+			}
+		}
+		s = append(s, c)
+	}
+	return string(s)
+}
+
+// readHexString reads a string after its "<", whitespaces are ignored and a
+// missing final digit is read as 0
+func (l *lexer) readHexString() string {
+	s := make([]byte, 0)
+	high, odd := byte(0), false
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		if c == '>' {
+			break
+		}
+
+		var nibble byte
+		switch {
+		case c >= '0' && c <= '9':
+			nibble = c - '0'
+		case c >= 'a' && c <= 'f':
+			nibble = c - 'a' + 10
+		case c >= 'A' && c <= 'F':
+			nibble = c - 'A' + 10
+		default:
+			continue
+		}
+
+		if odd {
+			s = append(s, high<<4|nibble)
+		} else {
+			high = nibble
+		}
+		odd = !odd
+	}
+	if odd {
+		s = append(s, high<<4)
+	}
+	return string(s)
+}
+
+// skipInlineImage skips the data of an inline image after the ID operator,
+// the data ends with whitespace followed by EI
+func (l *lexer) skipInlineImage() {
+	l.pos++ // the whitespace after ID
+	for l.pos+1 < len(l.data) {
+		if isWhitespace(l.data[l.pos]) &&
+			l.peek(1) == 'E' && l.peek(2) == 'I' &&
+			(l.pos+3 >= len(l.data) || !isRegular(l.data[l.pos+3])) {
+			l.pos += 3
+			return
+		}
+		l.pos++
+	}
+	l.pos = len(l.data)
+}
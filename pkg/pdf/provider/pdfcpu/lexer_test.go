@@ -0,0 +1,121 @@
+package pdfcpu
+
+import (
+	"io"
+	"reflect"
+	"testing"
+)
+
+func lexAll(t *testing.T, data string) []token {
+	t.Helper()
+
+	lex := newLexer([]byte(data))
+	tokens := make([]token, 0)
+	for {
+		tok, err := lex.next()
+		if err == io.EOF {
+			return tokens
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		tokens = append(tokens, tok)
+	}
+}
+
+func str(s string) token {
+	return token{Kind: tokenString, Value: s}
+}
+
+func num(word string, n float64) token {
+	return token{Kind: tokenNumber, Value: word, Number: n}
+}
+
+func op(s string) token {
+	return token{Kind: tokenOperator, Value: s}
+}
+
+func name(s string) token {
+	return token{Kind: tokenName, Value: s}
+}
+
+func TestLexer(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []token
+	}{
+		{name: "escaped parenthesis", data: `(a\)b)`, want: []token{str("a)b")}},
+		{name: "balanced parentheses", data: `(a(b)c)`, want: []token{str("a(b)c")}},
+		{name: "escapes", data: `(a\nb\t\\\(c)`, want: []token{str("a\nb\t\\(c")}},
+		{name: "octal escapes", data: `(\101\60\0053)`, want: []token{str("A0\x053")}},
+		{name: "line continuation", data: "(ab\\\ncd\\\r\nef)", want: []token{str("abcdef")}},
+		{name: "end of line", data: "(a\r\nb\rc)", want: []token{str("a\nb\nc")}},
+		{name: "unterminated string", data: `(abc`, want: []token{str("abc")}},
+		{name: "hex string", data: `<48 65 6C6c 6F>`, want: []token{str("Hello")}},
+		{name: "odd hex string", data: `<414>`, want: []token{str("A@")}},
+		{name: "empty hex string", data: `<>`, want: []token{str("")}},
+		{
+			name: "several operators on one line",
+			data: `BT /F1 12 Tf 72 712.5 Td (Hi) Tj ET`,
+			want: []token{
+				op("BT"), name("F1"), num("12", 12), op("Tf"),
+				num("72", 72), num("712.5", 712.5), op("Td"),
+				str("Hi"), op("Tj"), op("ET"),
+			},
+		},
+		{
+			name: "operators without whitespace",
+			data: `/F1 9 Tf[(A)-250(B)]TJ`,
+			want: []token{
+				name("F1"), num("9", 9), op("Tf"),
+				{Kind: tokenArrayStart, Value: "["}, str("A"), num("-250", -250), str("B"), {Kind: tokenArrayEnd, Value: "]"},
+				op("TJ"),
+			},
+		},
+		{
+			name: "quote operators",
+			data: `(A) ' 1 2 (B) "`,
+			want: []token{str("A"), op("'"), num("1", 1), num("2", 2), str("B"), op(`"`)},
+		},
+		{name: "numbers", data: `-.5 12. +3`, want: []token{num("-.5", -0.5), num("12.", 12), num("+3", 3)}},
+		{name: "malformed number", data: `1.2.3`, want: []token{op("1.2.3")}},
+		{name: "escaped name", data: `/A#20B`, want: []token{name("A B")}},
+		{
+			name: "dictionary",
+			data: `<< /MCID 0 >> BDC`,
+			want: []token{{Kind: tokenDictStart, Value: "<<"}, name("MCID"), num("0", 0), {Kind: tokenDictEnd, Value: ">>"}, op("BDC")},
+		},
+		{name: "comment", data: "% (ignored) Tj\nq", want: []token{op("q")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lexAll(t, tt.data); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("next() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLexerInlineImage(t *testing.T) {
+	lex := newLexer([]byte("BI /W 2 /H 1 /BPC 8 ID \x00(\xffEI) Tj\nEI Q"))
+	for {
+		tok, err := lex.next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tok.Kind == tokenOperator && tok.Value == "ID" {
+			break
+		}
+	}
+
+	lex.skipInlineImage()
+	tok, err := lex.next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := op("Q"); tok != want {
+		t.Errorf("next() after the image = %+v, want %+v", tok, want)
+	}
+}
@@ -1,225 +1,272 @@
 package pdfcpu
 
 import (
-	"bufio"
 	"io"
-	"math"
-	"regexp"
 	"sort"
-	"strconv"
-	"strings"
 
 	"github.com/mrrizkin/omniscan/pkg/pdf/provider/pdfcpu/encoder"
 )
 
-var (
-	fontRegex      = regexp.MustCompile(`\/(F\d+) (\d+\.?\d*) Tf`)
-	textRegex      = regexp.MustCompile(`\(([^)]+)\)\s*Tj`)
-	textArrayRegex = regexp.MustCompile(`\[*((?:\(([^)]+)\)+)+)\]*\s*TJ`)
-	posRegex       = regexp.MustCompile(`(\d+\.?\d*) (\d+\.?\d*) Td`)
-	tmRegex        = regexp.MustCompile(`(\d+\.?\d*)\s+(\d+\.?\d*)\s+(\d+\.?\d*)\s+(\d+\.?\d*)\s+(\d+\.?\d*)\s+(\d+\.?\d*)\s+Tm`)
-	bfCharRegex    = regexp.MustCompile(`<([0-9A-Fa-f]+)>\s*<([0-9A-Fa-f]+)>`)
-	bfRangeRegex   = regexp.MustCompile(`<([0-9A-Fa-f]+)>\s*<([0-9A-Fa-f]+)>\s*<([0-9A-Fa-f]+)>`)
-)
+// operand of a content stream operator, arrays and dictionaries keep their
+// items in Items, a dictionary as key and value pairs
+type operand struct {
+	token
+	Items []operand
+}
 
 type parserState struct {
-	State             string
-	CurrentFont       *fontObject
-	CurrentFontSize   float64
-	CurrentTextObject TextObject
-	TextObjects       Content
-}
+	fonts fonts
 
-func (p *PDFCPU) parse(r io.Reader) (Content, error) {
-	scanner := bufio.NewScanner(r)
+	graphicsState
+	stack []graphicsState
+	text  textMatrices
+
+	InText bool
+	writer textWriter
+}
 
-	var result Content
-	state := parserState{
-		State: "INITIAL",
+// parse reads the text of a page content stream, fonts are the fonts of
+// the page by resource name
+func (p *PDFCPU) parse(r io.Reader, fonts fonts) (Content, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
 	}
 
-	textFound := 0
-	for scanner.Scan() {
-		line := scanner.Text()
-		switch state.State {
-		case "INITIAL":
-			if strings.HasPrefix(line, "BT") { // Begin Text block
-				state.State = "TEXT_BLOCK"
-				state.CurrentTextObject = TextObject{}
-
-				if state.CurrentFont != nil {
-					state.CurrentTextObject.FontName = state.CurrentFont.FontName
-					state.CurrentTextObject.FontSize = state.CurrentFontSize
-				}
-			}
+	lex := newLexer(data)
+	state := parserState{fonts: fonts, graphicsState: newGraphicsState()}
+	operands := make([]operand, 0)
+	for {
+		tok, err := lex.next()
+		if err == io.EOF {
+			break
+		}
 
-		case "TEXT_BLOCK":
-			// Parse font name
-			if fontMatch := fontRegex.FindStringSubmatch(line); len(fontMatch) > 2 {
-				if font, ok := p.fonts.Get(fontMatch[1]); ok {
-					state.CurrentTextObject.FontName = font.FontName
-					state.CurrentFont = font
-				}
-				fontSize, _ := strconv.ParseFloat(fontMatch[2], 64)
-				state.CurrentTextObject.FontSize = fontSize
-				state.CurrentFontSize = fontSize
+		switch tok.Kind {
+		case tokenOperator:
+			p.execute(&state, tok.Value, operands)
+			if tok.Value == "ID" {
+				lex.skipInlineImage()
 			}
+			operands = operands[:0]
+		case tokenArrayStart, tokenDictStart:
+			operands = append(operands, readContainer(lex, tok))
+		case tokenArrayEnd, tokenDictEnd:
+			// unbalanced, nothing to close
+		default:
+			operands = append(operands, operand{token: tok})
+		}
+	}
 
-			// Parse text content Tj
-			if textMatch := textRegex.FindStringSubmatch(line); len(textMatch) > 1 {
-				state.CurrentTextObject.Text += decodeText(line, textMatch[1], state.CurrentFont)
-				textFound++
-			}
+	content := state.writer.objects
+	sort.Sort(content)
+	return mergeWords(content), nil
+}
 
-			// Parse text content TJ
-			if textArrayMatch := textArrayRegex.FindStringSubmatch(line); len(textArrayMatch) > 2 {
-				state.CurrentTextObject.Text += decodeText(line, textArrayMatch[2], state.CurrentFont)
-				textFound++
-			}
+// readContainer reads the items of an array or a dictionary up to its
+// closing token
+func readContainer(lex *lexer, start token) operand {
+	container := operand{token: start, Items: make([]operand, 0)}
+	for {
+		tok, err := lex.next()
+		if err == io.EOF {
+			return container
+		}
 
-			// Parse position
-			if posMatch := posRegex.FindStringSubmatch(line); len(posMatch) > 2 {
-				x, _ := strconv.ParseFloat(posMatch[1], 64)
-				y, _ := strconv.ParseFloat(posMatch[2], 64)
-				if state.CurrentTextObject.Position.Y != 0 &&
-					math.Abs(state.CurrentTextObject.Position.Y-y) > state.CurrentFontSize {
-					state.CurrentTextObject.Text += "\n"
-				}
-				state.CurrentTextObject.Position.X += x
-				state.CurrentTextObject.Position.Y += y
-			}
+		switch tok.Kind {
+		case tokenArrayEnd, tokenDictEnd:
+			return container
+		case tokenArrayStart, tokenDictStart:
+			container.Items = append(container.Items, readContainer(lex, tok))
+		default:
+			container.Items = append(container.Items, operand{token: tok})
+		}
+	}
+}
 
-			// Parse position using Tm operator
-			if tmMatch := tmRegex.FindStringSubmatch(line); len(tmMatch) > 6 {
-				x, _ := strconv.ParseFloat(tmMatch[5], 64)
-				y, _ := strconv.ParseFloat(tmMatch[6], 64)
-				if state.CurrentTextObject.Position.Y != 0 &&
-					math.Abs(state.CurrentTextObject.Position.Y-y) > state.CurrentFontSize {
-					state.CurrentTextObject.Text += "\n"
-				}
-				state.CurrentTextObject.Position = Position{X: x, Y: y}
-			}
+// execute applies a text or graphics state operator, the other operators
+// are ignored. An operator with missing operands is ignored as well.
+func (p *PDFCPU) execute(state *parserState, operator string, operands []operand) {
+	switch operator {
+	case "q":
+		state.stack = append(state.stack, state.graphicsState)
 
-			// End of text block
-			if strings.HasPrefix(line, "ET") {
-				state.State = "INITIAL"
-				textFound = 0
-				if state.CurrentTextObject.Text != "" {
-					result = append(result, state.CurrentTextObject)
-				}
-			}
+	case "Q":
+		if n := len(state.stack); n > 0 {
+			state.graphicsState = state.stack[n-1]
+			state.stack = state.stack[:n-1]
 		}
-	}
 
-	if err := scanner.Err(); err != nil {
-		return nil, err
-	}
+	case "cm":
+		if m, ok := matrixOf(operands); ok {
+			state.CTM = m.mul(state.CTM)
+		}
 
-	sort.Sort(result)
-	return result, nil
-}
+	case "BT": // Begin Text block
+		state.InText = true
+		state.text = textMatrices{Tm: identity, Tlm: identity}
 
-func sanitizeIdentityH(text string) string {
-	text = strings.TrimSpace(text)
-	if strings.HasPrefix(text, "(") {
-		text = text[1:]
-	}
-	if strings.HasSuffix(text, "Tj") {
-		text = text[:len(text)-2]
+	case "ET": // End Text block
+		state.InText = false
+
+	case "Tf":
+		args, ok := operandsOf(operands, tokenName, tokenNumber)
+		if !ok {
+			return
+		}
+		// a font missing from the page resources is looked up in the
+		// whole document, an unknown font is decoded as is
+		font, ok := state.fonts.Get(args[0].Value)
+		if !ok {
+			font, _ = p.fonts.Get(args[0].Value)
+		}
+		state.Font = font
+		state.FontSize = args[1].Number
+
+	case "Tc", "Tw", "Tz", "TL", "Ts":
+		args, ok := operandsOf(operands, tokenNumber)
+		if !ok {
+			return
+		}
+		switch operator {
+		case "Tc":
+			state.CharSpacing = args[0].Number
+		case "Tw":
+			state.WordSpacing = args[0].Number
+		case "Tz":
+			state.HorizontalScaling = args[0].Number / 100
+		case "TL":
+			state.Leading = args[0].Number
+		case "Ts":
+			state.Rise = args[0].Number
+		}
 	}
-	text = strings.TrimSpace(text)
-	if strings.HasSuffix(text, ")") {
-		text = text[:len(text)-1]
+
+	if !state.InText {
+		return
 	}
 
-	tmp := []byte("")
-	reader := strings.NewReader(text)
-	depth := 1
-Loop:
-	for {
-		if reader.Len() == 0 {
-			break
+	switch operator {
+	case "Td", "TD":
+		args, ok := operandsOf(operands, tokenNumber, tokenNumber)
+		if !ok {
+			return
+		}
+		if operator == "TD" {
+			state.Leading = -args[1].Number
 		}
+		state.text.nextLine(args[0].Number, args[1].Number)
 
-		c, err := reader.ReadByte()
-		if err != nil {
-			break
+	case "Tm":
+		if m, ok := matrixOf(operands); ok {
+			state.text = textMatrices{Tm: m, Tlm: m}
 		}
 
-		switch c {
-		default:
-			tmp = append(tmp, c)
-		case '(':
-			depth++
-			tmp = append(tmp, c)
-		case ')':
-			if depth--; depth == 0 {
-				break Loop
-			}
-			tmp = append(tmp, c)
-		case '\\':
-			c, err := reader.ReadByte()
-			if err != nil {
-				break
-			}
-			switch c {
-			default:
-				reader.UnreadByte()
-				tmp = append(tmp, '\\', c)
-			case 'n':
-				tmp = append(tmp, '\n')
-			case 'r':
-				tmp = append(tmp, '\r')
-			case 'b':
-				tmp = append(tmp, '\b')
-			case 't':
-				tmp = append(tmp, '\t')
-			case 'f':
-				tmp = append(tmp, '\f')
-			case '(', ')', '\\':
-				tmp = append(tmp, c)
-			case '\r':
-				if innerC, err := reader.ReadByte(); err == nil {
-					if innerC != '\n' {
-						reader.UnreadByte()
-					}
-				}
-				fallthrough
-			case '\n':
-				// no append
-			case '0', '1', '2', '3', '4', '5', '6', '7':
-				x := int(c - '0')
-				for i := 0; i < 2; i++ {
-					if c, err := reader.ReadByte(); err == nil {
-						if c < '0' || c > '7' {
-							reader.UnreadByte()
-							break
-						}
-						x = x*8 + int(c-'0')
-					}
-				}
-				if x > 255 {
-					reader.UnreadByte()
-					break
-				}
-				tmp = append(tmp, byte(x))
-			}
+	case "T*":
+		state.text.nextLine(0, -state.Leading)
+
+	case "Tj":
+		if args, ok := operandsOf(operands, tokenString); ok {
+			p.show(state, args)
+		}
+
+	case "'":
+		if args, ok := operandsOf(operands, tokenString); ok {
+			state.text.nextLine(0, -state.Leading)
+			p.show(state, args)
+		}
+
+	case "\"":
+		if args, ok := operandsOf(operands, tokenNumber, tokenNumber, tokenString); ok {
+			state.WordSpacing = args[0].Number
+			state.CharSpacing = args[1].Number
+			state.text.nextLine(0, -state.Leading)
+			p.show(state, args[2:])
+		}
+
+	case "TJ":
+		if args, ok := operandsOf(operands, tokenArrayStart); ok {
+			p.show(state, args[0].Items)
+		}
+	}
+}
+
+// show adds the strings shown by one operator as a text run starting at
+// the current text position in page space, the numbers between the strings
+// of a TJ array move the position and may split the run.
+func (p *PDFCPU) show(state *parserState, items []operand) {
+	state.writer.start(state.textObject())
+	for _, item := range items {
+		switch item.Kind {
+		case tokenString:
+			text := decodeText(item.Value, state.Font)
+			state.advance(&state.text, item.Value)
+			state.writer.write(text, state.position().X)
+		case tokenNumber:
+			end := state.position().X
+			state.adjust(&state.text, item.Number)
+			state.writer.gap(state.position().X-end, state.textObject())
 		}
 	}
+	state.writer.flush()
+}
+
+// textObject starts a text run at the current text position
+func (state *parserState) textObject() TextObject {
+	object := TextObject{
+		FontSize: state.FontSize * state.text.Tm.mul(state.CTM).verticalScale(),
+		Position: state.position(),
+	}
+	if state.Font != nil {
+		object.FontName = state.Font.FontName
+	}
+	return object
+}
+
+// position is the current text position in page space
+func (state *parserState) position() Position {
+	trm := state.renderingMatrix(state.text.Tm)
+	return Position{X: trm[4], Y: trm[5]}
+}
+
+// matrixOf returns the matrix given by the last six operands
+func matrixOf(operands []operand) (matrix, bool) {
+	args, ok := operandsOf(operands, tokenNumber, tokenNumber, tokenNumber, tokenNumber, tokenNumber, tokenNumber)
+	if !ok {
+		return matrix{}, false
+	}
 
-	return string(tmp)
+	var m matrix
+	for i, arg := range args {
+		m[i] = arg.Number
+	}
+	return m, true
 }
 
-func decodeText(raw, text string, font *fontObject) string {
-	if encoder.IsPDFDocEncoded(text) {
-		return encoder.PdfDocDecode(text)
-	} else if encoder.IsUTF16(text) {
-		return encoder.Utf16Decode(text[2:])
-	} else {
-		if font != nil {
-			return font.Decode(sanitizeIdentityH(raw))
-		} else {
-			return text
+// operandsOf returns the last operands when they have the given kinds
+func operandsOf(operands []operand, kinds ...tokenKind) ([]operand, bool) {
+	if len(operands) < len(kinds) {
+		return nil, false
+	}
+
+	args := operands[len(operands)-len(kinds):]
+	for i, kind := range kinds {
+		if args[i].Kind != kind {
+			return nil, false
 		}
 	}
+	return args, true
+}
+
+// decodeText decodes a shown string with the encoding of the font, strings
+// of fonts without one are read as PDFDocEncoding or UTF-16 when valid
+func decodeText(s string, font *fontObject) string {
+	if font != nil && font.enc != nil {
+		return font.Decode(s)
+	} else if encoder.IsPDFDocEncoded(s) {
+		return encoder.PdfDocDecode(s)
+	} else if encoder.IsUTF16(s) {
+		return encoder.Utf16Decode(s[2:])
+	}
+	return s
 }
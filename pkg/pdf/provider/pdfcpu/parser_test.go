@@ -0,0 +1,109 @@
+package pdfcpu
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// the content streams below use a font missing from the resources, its
+// glyphs are averageGlyphWidth wide, 5 units at size 10
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    Content
+	}{
+		{
+			name:    "Tj",
+			content: `BT /F1 10 Tf 1 0 0 1 100 700 Tm (Hello) Tj ET`,
+			want:    Content{{Text: "Hello", FontSize: 10, Position: Position{X: 100, Y: 700}, Width: 25}},
+		},
+		{
+			name:    "escaped parenthesis",
+			content: `BT /F1 10 Tf 100 700 Td (a\)b) Tj ET`,
+			want:    Content{{Text: "a)b", FontSize: 10, Position: Position{X: 100, Y: 700}, Width: 15}},
+		},
+		{
+			name:    "octal escapes",
+			content: `BT /F1 10 Tf 100 700 Td (\101\102) Tj ET`,
+			want:    Content{{Text: "AB", FontSize: 10, Position: Position{X: 100, Y: 700}, Width: 10}},
+		},
+		{
+			name:    "hex string",
+			content: `BT /F1 10 Tf 100 700 Td <414243> Tj ET`,
+			want:    Content{{Text: "ABC", FontSize: 10, Position: Position{X: 100, Y: 700}, Width: 15}},
+		},
+		{
+			name:    "several operators on one line",
+			content: "BT /F1 10 Tf 100 700 Td (A) Tj 0 -12 Td (B) Tj ET",
+			want: Content{
+				{Text: "A", FontSize: 10, Position: Position{X: 100, Y: 700}, Width: 5},
+				{Text: "B", FontSize: 10, Position: Position{X: 100, Y: 688}, Width: 5},
+			},
+		},
+		{
+			name:    "quote moves to the next line",
+			content: "BT /F1 10 Tf 12 TL 100 700 Td (A) Tj\n(B) ' ET",
+			want: Content{
+				{Text: "A", FontSize: 10, Position: Position{X: 100, Y: 700}, Width: 5},
+				{Text: "B", FontSize: 10, Position: Position{X: 100, Y: 688}, Width: 5},
+			},
+		},
+		{
+			name:    "double quote sets the spacing",
+			content: "BT /F1 10 Tf 12 TL 100 700 Td 2 1 (AB) \" ET",
+			want:    Content{{Text: "AB", FontSize: 10, Position: Position{X: 100, Y: 688}, Width: 12}},
+		},
+		{
+			name:    "TJ word gap",
+			content: `BT /F1 10 Tf 100 700 Td [(A) -200 (B)] TJ ET`,
+			want:    Content{{Text: "A B", FontSize: 10, Position: Position{X: 100, Y: 700}, Width: 12}},
+		},
+		{
+			name:    "TJ kerning",
+			content: `BT /F1 10 Tf 100 700 Td [(A) 50 (B)] TJ ET`,
+			want:    Content{{Text: "AB", FontSize: 10, Position: Position{X: 100, Y: 700}, Width: 9.5}},
+		},
+		{
+			name:    "TJ column gap",
+			content: `BT /F1 10 Tf 100 700 Td [(A) -1000 (B)] TJ ET`,
+			want: Content{
+				{Text: "A", FontSize: 10, Position: Position{X: 100, Y: 700}, Width: 5},
+				{Text: "B", FontSize: 10, Position: Position{X: 115, Y: 700}, Width: 5},
+			},
+		},
+		{
+			name: "inline image",
+			content: "BT /F1 10 Tf 100 700 Td (A) Tj ET\n" +
+				"BI /W 2 /H 1 /BPC 8 /CS /G ID \x00(\xff) Tj\nEI\n" +
+				"BT /F1 10 Tf 100 600 Td (B) Tj ET",
+			want: Content{
+				{Text: "A", FontSize: 10, Position: Position{X: 100, Y: 700}, Width: 5},
+				{Text: "B", FontSize: 10, Position: Position{X: 100, Y: 600}, Width: 5},
+			},
+		},
+		{
+			name:    "text outside BT",
+			content: `/F1 10 Tf (A) Tj BT 100 700 Td (B) Tj ET`,
+			want:    Content{{Text: "B", FontSize: 10, Position: Position{X: 100, Y: 700}, Width: 5}},
+		},
+		{
+			name:    "missing operands",
+			content: `BT /F1 10 Tf 100 700 Td Tj (A) Td (B) Tj ET`,
+			want:    Content{{Text: "B", FontSize: 10, Position: Position{X: 100, Y: 700}, Width: 5}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := (&PDFCPU{}).parse(strings.NewReader(tt.content), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
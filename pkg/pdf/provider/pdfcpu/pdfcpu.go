@@ -17,8 +17,9 @@ import (
 
 type (
 	PDFCPU struct {
-		ctx   *model.Context
-		fonts fonts
+		ctx     *model.Context
+		fonts   fonts
+		objects map[int]*fontObject
 	}
 
 	Content []TextObject
@@ -42,15 +43,20 @@ func NewReader(filename string, b []byte) (*PDFCPU, error) {
 		return nil, fmt.Errorf("invalid context")
 	}
 
-	fonts, err := GetFonts(ctx)
+	objects := GetFonts(ctx)
+	return &PDFCPU{
+		ctx:     ctx,
+		fonts:   documentFonts(objects),
+		objects: objects,
+	}, nil
+}
+
+func Open(filename string, b []byte) (pdf.PDFReader, error) {
+	reader, err := NewReader(filename, b)
 	if err != nil {
 		return nil, err
 	}
-
-	return &PDFCPU{
-		ctx:   ctx,
-		fonts: fonts,
-	}, nil
+	return reader, nil
 }
 
 func (p *PDFCPU) Page(page int) (pdf.PDFPage, error) {
@@ -71,7 +77,7 @@ func (p *PDFCPU) Page(page int) (pdf.PDFPage, error) {
 		return nil, err
 	}
 
-	content, err := p.parse(r)
+	content, err := p.parse(r, pageFonts(p.ctx, p.objects, page))
 	if err != nil {
 		return nil, err
 	}
@@ -96,6 +102,7 @@ func (p *Page) GetTextByRow(tolerance float64) (types.Rows, error) {
 					FontSize: object.FontSize,
 					X:        object.Position.X,
 					Y:        object.Position.Y,
+					Width:    object.Width,
 					S:        object.Text,
 				}},
 				Position: int64(object.Position.Y),
@@ -111,6 +118,7 @@ func (p *Page) GetTextByRow(tolerance float64) (types.Rows, error) {
 				FontSize: object.FontSize,
 				X:        object.Position.X,
 				Y:        object.Position.Y,
+				Width:    object.Width,
 				S:        object.Text,
 			})
 
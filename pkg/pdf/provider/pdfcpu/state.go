@@ -0,0 +1,90 @@
+package pdfcpu
+
+import "math"
+
+// matrix is a transformation matrix [a b c d e f], a point is transformed
+// as x' = a*x + c*y + e and y' = b*x + d*y + f, see PDF 32000-1:2008, 8.3.3
+type matrix [6]float64
+
+var identity = matrix{1, 0, 0, 1, 0, 0}
+
+func translate(tx, ty float64) matrix {
+	return matrix{1, 0, 0, 1, tx, ty}
+}
+
+// mul returns m × n, the transformation m followed by n
+func (m matrix) mul(n matrix) matrix {
+	return matrix{
+		m[0]*n[0] + m[1]*n[2],
+		m[0]*n[1] + m[1]*n[3],
+		m[2]*n[0] + m[3]*n[2],
+		m[2]*n[1] + m[3]*n[3],
+		m[4]*n[0] + m[5]*n[2] + n[4],
+		m[4]*n[1] + m[5]*n[3] + n[5],
+	}
+}
+
+// verticalScale is the length of the unit vertical vector once transformed
+func (m matrix) verticalScale() float64 {
+	return math.Hypot(m[2], m[3])
+}
+
+// graphicsState holds the parameters saved by q and restored by Q, the
+// text state parameters are part of it, see PDF 32000-1:2008, 8.4 and 9.3
+type graphicsState struct {
+	CTM               matrix
+	Font              *fontObject
+	FontSize          float64
+	CharSpacing       float64
+	WordSpacing       float64
+	HorizontalScaling float64
+	Leading           float64
+	Rise              float64
+}
+
+func newGraphicsState() graphicsState {
+	return graphicsState{CTM: identity, HorizontalScaling: 1}
+}
+
+// textMatrices are the text matrix and the text line matrix, they only
+// live between BT and ET
+type textMatrices struct {
+	Tm  matrix
+	Tlm matrix
+}
+
+// nextLine moves to the start of the next line offset by tx and ty
+func (t *textMatrices) nextLine(tx, ty float64) {
+	t.Tlm = translate(tx, ty).mul(t.Tlm)
+	t.Tm = t.Tlm
+}
+
+// renderingMatrix maps text space to page space for the next glyph, see
+// PDF 32000-1:2008, 9.4.4
+func (gs *graphicsState) renderingMatrix(tm matrix) matrix {
+	return matrix{gs.FontSize * gs.HorizontalScaling, 0, 0, gs.FontSize, 0, gs.Rise}.
+		mul(tm).
+		mul(gs.CTM)
+}
+
+// advance moves the text matrix past raw shown with the current font, the
+// glyph widths are in thousandths of text space units
+func (gs *graphicsState) advance(t *textMatrices, raw string) {
+	tx := 0.0
+	for _, code := range gs.Font.codes(raw) {
+		w := gs.Font.glyphWidth(code)/1000*gs.FontSize + gs.CharSpacing
+		if code == " " {
+			// word spacing only applies to the single byte code 32
+			w += gs.WordSpacing
+		}
+		tx += w * gs.HorizontalScaling
+	}
+	t.Tm = translate(tx, 0).mul(t.Tm)
+}
+
+// adjust applies a number of a TJ array, it is in thousandths of text
+// space units and moves the next glyph to the left
+func (gs *graphicsState) adjust(t *textMatrices, n float64) {
+	tx := -n / 1000 * gs.FontSize * gs.HorizontalScaling
+	t.Tm = translate(tx, 0).mul(t.Tm)
+}
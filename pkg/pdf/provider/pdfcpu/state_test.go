@@ -0,0 +1,169 @@
+package pdfcpu
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestTextPosition checks the page space position of the shown text, the
+// glyphs are averageGlyphWidth wide as the font is missing
+func TestTextPosition(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []TextObject
+	}{
+		{
+			name:    "translated CTM",
+			content: `1 0 0 1 50 20 cm BT /F1 10 Tf 100 700 Td (A) Tj ET`,
+			want:    []TextObject{{Text: "A", FontSize: 10, Position: Position{X: 150, Y: 720}, Width: 5}},
+		},
+		{
+			name:    "scaled CTM",
+			content: `2 0 0 2 0 0 cm BT /F1 10 Tf 10 20 Td (A) Tj ET`,
+			want:    []TextObject{{Text: "A", FontSize: 20, Position: Position{X: 20, Y: 40}, Width: 10}},
+		},
+		{
+			name:    "concatenated CTM",
+			content: `1 0 0 1 100 0 cm 2 0 0 2 0 0 cm BT /F1 10 Tf 10 20 Td (A) Tj ET`,
+			want:    []TextObject{{Text: "A", FontSize: 20, Position: Position{X: 120, Y: 40}, Width: 10}},
+		},
+		{
+			name:    "negative CTM translation",
+			content: `1 0 0 1 -50 -20 cm BT /F1 10 Tf 100 700 Td (A) Tj ET`,
+			want:    []TextObject{{Text: "A", FontSize: 10, Position: Position{X: 50, Y: 680}, Width: 5}},
+		},
+		{
+			name:    "negative text position",
+			content: `BT /F1 10 Tf -10 -5 Td (A) Tj ET`,
+			want:    []TextObject{{Text: "A", FontSize: 10, Position: Position{X: -10, Y: -5}, Width: 5}},
+		},
+		{
+			name:    "flipped page",
+			content: `1 0 0 -1 0 842 cm BT /F1 10 Tf 1 0 0 -1 100 742 Tm (A) Tj ET`,
+			want:    []TextObject{{Text: "A", FontSize: 10, Position: Position{X: 100, Y: 100}, Width: 5}},
+		},
+		{
+			name: "nested q and Q",
+			content: "q 1 0 0 1 100 0 cm\n" +
+				"q 1 0 0 1 0 100 cm BT /F1 10 Tf 0 0 Td (A) Tj ET Q\n" +
+				"BT /F1 10 Tf 0 0 Td (B) Tj ET Q\n" +
+				"BT /F1 10 Tf 0 0 Td (C) Tj ET",
+			want: []TextObject{
+				{Text: "A", FontSize: 10, Position: Position{X: 100, Y: 100}, Width: 5},
+				{Text: "C", FontSize: 10, Position: Position{X: 0, Y: 0}, Width: 5},
+				{Text: "B", FontSize: 10, Position: Position{X: 100, Y: 0}, Width: 5},
+			},
+		},
+		{
+			name:    "Q restores the text state",
+			content: `BT /F1 10 Tf q /F1 20 Tf 3 Ts Q 100 700 Td (A) Tj ET`,
+			want:    []TextObject{{Text: "A", FontSize: 10, Position: Position{X: 100, Y: 700}, Width: 5}},
+		},
+		{
+			name:    "unbalanced Q",
+			content: `Q 1 0 0 1 10 10 cm Q BT /F1 10 Tf 100 700 Td (A) Tj ET`,
+			want:    []TextObject{{Text: "A", FontSize: 10, Position: Position{X: 110, Y: 710}, Width: 5}},
+		},
+		{
+			name:    "Td from the start of the line",
+			content: `BT /F1 10 Tf 100 700 Td (ABC) Tj 0 -12 Td (D) Tj ET`,
+			want: []TextObject{
+				{Text: "ABC", FontSize: 10, Position: Position{X: 100, Y: 700}, Width: 15},
+				{Text: "D", FontSize: 10, Position: Position{X: 100, Y: 688}, Width: 5},
+			},
+		},
+		{
+			name:    "TD sets the leading",
+			content: `BT /F1 10 Tf 100 700 Td 0 -14 TD (A) Tj T* (B) Tj ET`,
+			want: []TextObject{
+				{Text: "A", FontSize: 10, Position: Position{X: 100, Y: 686}, Width: 5},
+				{Text: "B", FontSize: 10, Position: Position{X: 100, Y: 672}, Width: 5},
+			},
+		},
+		{
+			name:    "T* with TL",
+			content: `BT /F1 10 Tf 12 TL 100 700 Td (A) Tj T* T* (B) Tj ET`,
+			want: []TextObject{
+				{Text: "A", FontSize: 10, Position: Position{X: 100, Y: 700}, Width: 5},
+				{Text: "B", FontSize: 10, Position: Position{X: 100, Y: 676}, Width: 5},
+			},
+		},
+		{
+			name:    "Tm replaces the position",
+			content: `BT /F1 10 Tf 100 700 Td 1 0 0 1 50 50 Tm (A) Tj 0 -10 Td (B) Tj ET`,
+			want: []TextObject{
+				{Text: "A", FontSize: 10, Position: Position{X: 50, Y: 50}, Width: 5},
+				{Text: "B", FontSize: 10, Position: Position{X: 50, Y: 40}, Width: 5},
+			},
+		},
+		{
+			name:    "scaled text matrix",
+			content: `BT /F1 1 Tf 12 0 0 12 100 700 Tm (AB) Tj 0 -1 Td (C) Tj ET`,
+			want: []TextObject{
+				{Text: "AB", FontSize: 12, Position: Position{X: 100, Y: 700}, Width: 12},
+				{Text: "C", FontSize: 12, Position: Position{X: 100, Y: 688}, Width: 6},
+			},
+		},
+		{
+			name:    "horizontal scaling",
+			content: `BT /F1 10 Tf 50 Tz 100 700 Td (AB) Tj ET`,
+			want:    []TextObject{{Text: "AB", FontSize: 10, Position: Position{X: 100, Y: 700}, Width: 5}},
+		},
+		{
+			name:    "rise",
+			content: `BT /F1 10 Tf 3 Ts 100 700 Td (A) Tj ET`,
+			want:    []TextObject{{Text: "A", FontSize: 10, Position: Position{X: 100, Y: 703}, Width: 5}},
+		},
+		{
+			name:    "BT resets the text matrix",
+			content: `BT /F1 10 Tf 100 700 Td ET BT (A) Tj ET`,
+			want:    []TextObject{{Text: "A", FontSize: 10, Position: Position{X: 0, Y: 0}, Width: 5}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := (&PDFCPU{}).parse(strings.NewReader(tt.content), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("parse() = %+v, want %+v", got, tt.want)
+			}
+			for i, want := range tt.want {
+				if got[i].Text != want.Text {
+					t.Errorf("object %d Text = %q, want %q", i, got[i].Text, want.Text)
+				}
+				if got[i].Position != want.Position {
+					t.Errorf("%s at X, Y = %v, %v, want %v, %v", want.Text, got[i].Position.X, got[i].Position.Y, want.Position.X, want.Position.Y)
+				}
+				if got[i].FontSize != want.FontSize || got[i].Width != want.Width {
+					t.Errorf("%s FontSize, Width = %v, %v, want %v, %v", want.Text, got[i].FontSize, got[i].Width, want.FontSize, want.Width)
+				}
+			}
+		})
+	}
+}
+
+func TestMatrixMul(t *testing.T) {
+	tests := []struct {
+		name string
+		m, n matrix
+		want matrix
+	}{
+		{name: "identity", m: translate(3, 4), n: identity, want: translate(3, 4)},
+		{name: "translations add up", m: translate(3, 4), n: translate(-1, 2), want: translate(2, 6)},
+		{name: "translate then scale", m: translate(3, 4), n: matrix{2, 0, 0, 2, 0, 0}, want: matrix{2, 0, 0, 2, 6, 8}},
+		{name: "scale then translate", m: matrix{2, 0, 0, 2, 0, 0}, n: translate(3, 4), want: matrix{2, 0, 0, 2, 3, 4}},
+		{name: "rotation", m: translate(1, 0), n: matrix{0, 1, -1, 0, 0, 0}, want: matrix{0, 1, -1, 0, 0, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.m.mul(tt.n); got != tt.want {
+				t.Errorf("mul() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package pdfcpu
+
+import (
+	"github.com/pdfcpu/pdfcpu/pkg/font"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// glyphWidths are the widths of the glyphs of a font by character code, in
+// thousandths of text space units, Missing is used for the other codes
+type glyphWidths struct {
+	Widths  map[int]float64
+	Missing float64
+}
+
+func (w *glyphWidths) width(code int) float64 {
+	if width, ok := w.Widths[code]; ok {
+		return width
+	}
+	return w.Missing
+}
+
+// getWidths reads the widths of a font, nil when the font doesn't declare
+// them and isn't one of the standard 14 fonts
+func getWidths(ctx *model.Context, fontName string, fontDict types.Dict) *glyphWidths {
+	if isComposite(fontDict) {
+		return getCIDWidths(ctx, fontDict)
+	}
+
+	if widths := getSimpleWidths(ctx, fontDict); widths != nil {
+		return widths
+	}
+
+	if font.IsCoreFont(fontName) {
+		widths := &glyphWidths{Widths: make(map[int]float64, 256)}
+		for code := 0; code < 256; code++ {
+			widths.Widths[code] = float64(font.CharWidth(fontName, rune(code)))
+		}
+		return widths
+	}
+
+	return nil
+}
+
+// getSimpleWidths reads /FirstChar and /Widths of a simple font, see PDF
+// 32000-1:2008, 9.6.2
+func getSimpleWidths(ctx *model.Context, fontDict types.Dict) *glyphWidths {
+	firstChar := fontDict.IntEntry("FirstChar")
+	obj, ok := fontDict.Find("Widths")
+	if firstChar == nil || !ok {
+		return nil
+	}
+
+	array, err := ctx.DereferenceArray(obj)
+	if err != nil || array == nil {
+		return nil
+	}
+
+	widths := &glyphWidths{Widths: make(map[int]float64, len(array))}
+	for i, item := range array {
+		if width, err := ctx.DereferenceNumber(item); err == nil {
+			widths.Widths[*firstChar+i] = width
+		}
+	}
+
+	if obj, ok := fontDict.Find("FontDescriptor"); ok {
+		if descriptor, err := ctx.DereferenceDict(obj); err == nil && descriptor != nil {
+			if obj, ok := descriptor.Find("MissingWidth"); ok {
+				widths.Missing, _ = ctx.DereferenceNumber(obj)
+			}
+		}
+	}
+	return widths
+}
+
+// maxCID is the largest CID, a /W range past it is cut so a broken or
+// hostile font can't make the range loop run for billions of codes
+const maxCID = 0xFFFF
+
+// getCIDWidths reads /DW and /W of the descendant font of a Type0 font,
+// the codes are the CIDs as the fonts are read with Identity encodings,
+// see PDF 32000-1:2008, 9.7.4.3
+func getCIDWidths(ctx *model.Context, fontDict types.Dict) *glyphWidths {
+	widths := &glyphWidths{Widths: make(map[int]float64), Missing: 1000}
+
+	obj, ok := fontDict.Find("DescendantFonts")
+	if !ok {
+		return widths
+	}
+	descendants, err := ctx.DereferenceArray(obj)
+	if err != nil || len(descendants) == 0 {
+		return widths
+	}
+	descendant, err := ctx.DereferenceDict(descendants[0])
+	if err != nil || descendant == nil {
+		return widths
+	}
+
+	if obj, ok := descendant.Find("DW"); ok {
+		if dw, err := ctx.DereferenceNumber(obj); err == nil {
+			widths.Missing = dw
+		}
+	}
+
+	obj, ok = descendant.Find("W")
+	if !ok {
+		return widths
+	}
+	array, err := ctx.DereferenceArray(obj)
+	if err != nil {
+		return widths
+	}
+
+	// the array mixes "c [w1 w2 ...]" and "cfirst clast w"
+	for i := 0; i < len(array); {
+		first, err := ctx.DereferenceNumber(array[i])
+		if err != nil || i+1 >= len(array) {
+			break
+		}
+
+		if list, err := ctx.DereferenceArray(array[i+1]); err == nil && list != nil {
+			for j, item := range list {
+				cid := int(first) + j
+				if cid < 0 || cid > maxCID {
+					continue
+				}
+				if width, err := ctx.DereferenceNumber(item); err == nil {
+					widths.Widths[cid] = width
+				}
+			}
+			i += 2
+			continue
+		}
+
+		if i+2 >= len(array) {
+			break
+		}
+		last, err := ctx.DereferenceNumber(array[i+1])
+		if err != nil {
+			break
+		}
+		width, err := ctx.DereferenceNumber(array[i+2])
+		if err != nil {
+			break
+		}
+		for cid := max(int(first), 0); cid <= min(int(last), maxCID); cid++ {
+			widths.Widths[cid] = width
+		}
+		i += 3
+	}
+
+	return widths
+}
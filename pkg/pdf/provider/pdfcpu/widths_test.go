@@ -0,0 +1,101 @@
+package pdfcpu
+
+import (
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+func type0Font(descendant types.Dict) types.Dict {
+	return types.Dict{
+		"Subtype":         types.Name("Type0"),
+		"DescendantFonts": types.Array{descendant},
+	}
+}
+
+func TestCIDWidths(t *testing.T) {
+	tests := []struct {
+		name        string
+		descendant  types.Dict
+		widths      map[int]float64
+		wantMissing float64
+		wantLen     int
+	}{
+		{
+			name:        "no widths",
+			descendant:  types.Dict{},
+			wantMissing: 1000,
+		},
+		{
+			name:        "default width",
+			descendant:  types.Dict{"DW": types.Integer(600)},
+			wantMissing: 600,
+		},
+		{
+			name: "list and range",
+			descendant: types.Dict{"W": types.Array{
+				types.Integer(1), types.Array{types.Integer(500), types.Float(612.5)},
+				types.Integer(10), types.Integer(12), types.Integer(700),
+			}},
+			widths:      map[int]float64{1: 500, 2: 612.5, 10: 700, 11: 700, 12: 700, 13: 1000},
+			wantMissing: 1000,
+			wantLen:     5,
+		},
+		{
+			name: "range past the largest CID",
+			descendant: types.Dict{"W": types.Array{
+				types.Integer(0xFFF0), types.Integer(1 << 40), types.Integer(700),
+			}},
+			widths:      map[int]float64{0xFFF0: 700, maxCID: 700, maxCID + 1: 1000},
+			wantMissing: 1000,
+			wantLen:     16,
+		},
+		{
+			name: "negative range",
+			descendant: types.Dict{"W": types.Array{
+				types.Integer(-5), types.Integer(2), types.Integer(300),
+			}},
+			widths:      map[int]float64{-1: 1000, 0: 300, 2: 300},
+			wantMissing: 1000,
+			wantLen:     3,
+		},
+		{
+			name: "list past the largest CID",
+			descendant: types.Dict{"W": types.Array{
+				types.Integer(maxCID), types.Array{types.Integer(400), types.Integer(500)},
+			}},
+			widths:      map[int]float64{maxCID: 400, maxCID + 1: 1000},
+			wantMissing: 1000,
+			wantLen:     1,
+		},
+		{
+			name: "truncated array",
+			descendant: types.Dict{"W": types.Array{
+				types.Integer(1), types.Array{types.Integer(500)},
+				types.Integer(10), types.Integer(12),
+			}},
+			widths:      map[int]float64{1: 500, 10: 1000},
+			wantMissing: 1000,
+			wantLen:     1,
+		},
+	}
+
+	ctx := &model.Context{XRefTable: &model.XRefTable{}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := getWidths(ctx, "", type0Font(tt.descendant))
+			if got.Missing != tt.wantMissing {
+				t.Errorf("Missing = %v, want %v", got.Missing, tt.wantMissing)
+			}
+			if len(got.Widths) != tt.wantLen {
+				t.Errorf("len(Widths) = %d, want %d", len(got.Widths), tt.wantLen)
+			}
+			for code, want := range tt.widths {
+				if width := got.width(code); width != want {
+					t.Errorf("width(%d) = %v, want %v", code, width, want)
+				}
+			}
+		})
+	}
+}
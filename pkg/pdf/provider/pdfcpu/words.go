@@ -0,0 +1,126 @@
+package pdfcpu
+
+import (
+	"math"
+	"strings"
+
+	"github.com/mrrizkin/omniscan/pkg/pdf/utils"
+)
+
+// gaps between two glyphs relative to the font size, a gap of wordGap is
+// read as a space and a gap of columnGap separates two texts
+const (
+	wordGap   = 0.15
+	columnGap = 0.6
+)
+
+// textWriter collects the text runs of a page and splits a run where its
+// TJ adjustments leave a gap as wide as a column gap
+type textWriter struct {
+	objects      Content
+	current      *TextObject
+	pendingSpace bool
+}
+
+func (w *textWriter) start(object TextObject) {
+	w.flush()
+	w.current = &object
+	w.pendingSpace = false
+}
+
+// write appends text shown up to x, the end of the run in page space
+func (w *textWriter) write(text string, x float64) {
+	if w.current == nil || text == "" {
+		return
+	}
+
+	if w.pendingSpace && !strings.HasPrefix(text, " ") {
+		w.current.Text += " "
+	}
+	w.pendingSpace = false
+	w.current.Text += text
+	w.current.Width = x - w.current.Position.X
+}
+
+// gap handles the space left between the end of the run and the next
+// glyph, next is the object the next glyph starts when the gap splits
+func (w *textWriter) gap(gap float64, next TextObject) {
+	if w.current == nil {
+		return
+	}
+
+	size := w.current.FontSize
+	switch {
+	case gap >= columnGap*size:
+		w.start(next)
+	case gap >= wordGap*size && !strings.HasSuffix(w.current.Text, " "):
+		w.pendingSpace = true
+	}
+}
+
+func (w *textWriter) flush() {
+	if w.current != nil && w.current.Text != "" {
+		w.objects = append(w.objects, *w.current)
+	}
+	w.current = nil
+}
+
+// runs whose baselines are less than lineTolerance apart, relative to the
+// font size, are on the same line, the producers round the positions of
+// the runs of a line differently
+const lineTolerance = 0.1
+
+// mergeWords joins the runs printed next to each other on the same
+// baseline with the same font, content must be sorted
+func mergeWords(content Content) Content {
+	words := make(Content, 0, len(content))
+	for _, object := range content {
+		if word := lineEnd(words, object); word != nil {
+			join(word, object)
+			continue
+		}
+		words = append(words, object)
+	}
+	return words
+}
+
+// lineEnd returns the word of the same line that object continues, nil
+// when object starts a new word. The runs of a line whose baselines differ
+// slightly are not next to each other once sorted, the words are searched
+// back up to the ones above the line.
+func lineEnd(words Content, object TextObject) *TextObject {
+	for i := len(words) - 1; i >= 0; i-- {
+		word := &words[i]
+		if word.Position.Y-object.Position.Y >= lineTolerance*math.Max(word.FontSize, object.FontSize) {
+			return nil
+		}
+		if !sameLine(*word, object) {
+			continue
+		}
+
+		gap := object.Position.X - (word.Position.X + word.Width)
+		size := math.Max(word.FontSize, object.FontSize)
+		if gap >= -wordGap*size && gap < columnGap*size {
+			return word
+		}
+	}
+	return nil
+}
+
+// join appends object to word, with a space when they are a word gap apart
+func join(word *TextObject, object TextObject) {
+	gap := object.Position.X - (word.Position.X + word.Width)
+	if gap >= wordGap*math.Max(word.FontSize, object.FontSize) &&
+		!strings.HasSuffix(word.Text, " ") &&
+		!strings.HasPrefix(object.Text, " ") {
+		word.Text += " "
+	}
+	word.Text += object.Text
+	word.Width = object.Position.X + object.Width - word.Position.X
+}
+
+func sameLine(a, b TextObject) bool {
+	return utils.IsEqualTolerance(a.Position.Y, b.Position.Y, lineTolerance*math.Max(a.FontSize, b.FontSize)) &&
+		a.FontName == b.FontName &&
+		math.Abs(a.FontSize-b.FontSize) < 0.5
+}
@@ -0,0 +1,121 @@
+package pdfcpu
+
+import (
+	"reflect"
+	"testing"
+)
+
+func run(text string, x, y, width float64) TextObject {
+	return TextObject{FontName: "Arial", Text: text, FontSize: 10, Position: Position{X: x, Y: y}, Width: width}
+}
+
+// the runs below are 10 units high, wordGap is 1.5 units and columnGap 6
+func TestMergeWords(t *testing.T) {
+	tests := []struct {
+		name    string
+		content Content
+		want    Content
+	}{
+		{
+			name:    "below the word gap",
+			content: Content{run("A", 100, 700, 5), run("B", 106.25, 700, 5)},
+			want:    Content{run("AB", 100, 700, 11.25)},
+		},
+		{
+			name:    "at the word gap",
+			content: Content{run("A", 100, 700, 5), run("B", 106.5, 700, 5)},
+			want:    Content{run("A B", 100, 700, 11.5)},
+		},
+		{
+			name:    "below the column gap",
+			content: Content{run("A", 100, 700, 5), run("B", 110.5, 700, 5)},
+			want:    Content{run("A B", 100, 700, 15.5)},
+		},
+		{
+			name:    "at the column gap",
+			content: Content{run("A", 100, 700, 5), run("B", 111, 700, 5)},
+			want:    Content{run("A", 100, 700, 5), run("B", 111, 700, 5)},
+		},
+		{
+			name:    "overlapping runs",
+			content: Content{run("A", 100, 700, 5), run("B", 103.5, 700, 5)},
+			want:    Content{run("AB", 100, 700, 8.5)},
+		},
+		{
+			name:    "run far to the left",
+			content: Content{run("A", 100, 700, 5), run("B", 103, 700, 5)},
+			want:    Content{run("A", 100, 700, 5), run("B", 103, 700, 5)},
+		},
+		{
+			name:    "space already shown",
+			content: Content{run("A ", 100, 700, 7), run("B", 109, 700, 5)},
+			want:    Content{run("A B", 100, 700, 14)},
+		},
+		{
+			name:    "baselines slightly apart",
+			content: Content{run("Saldo", 100, 700.04, 25), run("Awal", 126.5, 700, 20)},
+			want:    Content{run("Saldo Awal", 100, 700.04, 46.5)},
+		},
+		{
+			name: "columns with baselines slightly apart",
+			content: Content{
+				run("A", 100, 700.04, 5), run("C", 200, 700.04, 5),
+				run("B", 106.5, 700, 5), run("D", 206.5, 700, 5),
+			},
+			want: Content{run("A B", 100, 700.04, 11.5), run("C D", 200, 700.04, 11.5)},
+		},
+		{
+			name:    "next line",
+			content: Content{run("A", 100, 700, 5), run("B", 106.5, 699, 5)},
+			want:    Content{run("A", 100, 700, 5), run("B", 106.5, 699, 5)},
+		},
+		{
+			name: "other font",
+			content: Content{
+				run("A", 100, 700, 5),
+				{FontName: "Arial-Bold", Text: "B", FontSize: 10, Position: Position{X: 106.5, Y: 700}, Width: 5},
+			},
+			want: Content{
+				run("A", 100, 700, 5),
+				{FontName: "Arial-Bold", Text: "B", FontSize: 10, Position: Position{X: 106.5, Y: 700}, Width: 5},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeWords(tt.content); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeWords() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// a TJ run is split at a column gap and gets a space at a word gap
+func TestTextWriterGap(t *testing.T) {
+	tests := []struct {
+		name string
+		gap  float64
+		want Content
+	}{
+		{name: "below the word gap", gap: 1.25, want: Content{run("AB", 100, 700, 11.25)}},
+		{name: "at the word gap", gap: 1.5, want: Content{run("A B", 100, 700, 11.5)}},
+		{name: "below the column gap", gap: 5.75, want: Content{run("A B", 100, 700, 15.75)}},
+		{name: "at the column gap", gap: 6, want: Content{run("A", 100, 700, 5), run("B", 111, 700, 5)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var w textWriter
+			w.start(run("", 100, 700, 0))
+			w.write("A", 105)
+			w.gap(tt.gap, run("", 105+tt.gap, 700, 0))
+			w.write("B", 110+tt.gap)
+			w.flush()
+
+			if !reflect.DeepEqual(w.objects, tt.want) {
+				t.Errorf("objects = %+v, want %+v", w.objects, tt.want)
+			}
+		})
+	}
+}
@@ -28,6 +28,14 @@ func NewReader(filename string, b []byte) (*RSCPDF, error) {
 	}, nil
 }
 
+func Open(filename string, b []byte) (pdff.PDFReader, error) {
+	reader, err := NewReader(filename, b)
+	if err != nil {
+		return nil, err
+	}
+	return reader, nil
+}
+
 func (r *RSCPDF) NumPage() int {
 	return r.pdfReader.NumPage()
 }
@@ -47,6 +55,7 @@ func (r *RSCPDFPage) GetTextByRow(tolerance float64) (types.Rows, error) {
 				FontSize: text.FontSize,
 				X:        text.X,
 				Y:        text.Y,
+				Width:    text.W,
 				S:        text.S,
 			}
 		}
@@ -6,6 +6,7 @@ type (
 		FontSize float64
 		X        float64
 		Y        float64
+		Width    float64
 		S        string
 	}
 
@@ -18,3 +19,18 @@ type (
 
 	Rows []*Row
 )
+
+// averageGlyphWidth is the width of a glyph relative to the font size used
+// when the provider doesn't measure the text
+const averageGlyphWidth = 0.5
+
+// Bounds returns the box of the text in PDF points, from the baseline to
+// the font size above it. The width is estimated when the provider doesn't
+// measure it.
+func (t Text) Bounds() (x0, y0, x1, y1 float64) {
+	width := t.Width
+	if width <= 0 {
+		width = float64(len([]rune(t.S))) * t.FontSize * averageGlyphWidth
+	}
+	return t.X, t.Y, t.X + width, t.Y + t.FontSize
+}
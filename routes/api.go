@@ -13,6 +13,7 @@ func ApiRoutes(
 	userController *controllers.UserController,
 
 	eStatementController *api.EStatementController,
+	categoryController *api.CategoryController,
 ) {
 	api := app.ApiRoutes()
 	api.Get("/health", func(c *fiber.Ctx) error {
@@ -23,9 +24,26 @@ func ApiRoutes(
 	v1 := api.Group("/v1")
 
 	v1.Get("/e-statement", eStatementController.EStatementFindAll)
+	v1.Get("/e-statement/banks", eStatementController.EStatementBanks)
+	v1.Get("/e-statement/libraries", eStatementController.EStatementLibraries)
 	v1.Post("/e-statement/scan", eStatementController.EStatementScan)
 	v1.Get("/e-statement/:id/summary", eStatementController.EStatementGetSumary)
 
+	// Category routes
+	v1.Get("/categories", categoryController.CategoryFindAll)
+	v1.Get("/categories/:id", categoryController.CategoryFindByID)
+	v1.Post("/categories", categoryController.CategoryCreate)
+	v1.Put("/categories/:id", categoryController.CategoryUpdate)
+	v1.Delete("/categories/:id", categoryController.CategoryDelete)
+
+	v1.Get("/category-rules", categoryController.CategoryRuleFindAll)
+	v1.Post("/category-rules/preview", categoryController.CategoryRulePreview)
+	v1.Post("/category-rules/apply", categoryController.CategoryRuleApply)
+	v1.Get("/category-rules/:id", categoryController.CategoryRuleFindByID)
+	v1.Post("/category-rules", categoryController.CategoryRuleCreate)
+	v1.Put("/category-rules/:id", categoryController.CategoryRuleUpdate)
+	v1.Delete("/category-rules/:id", categoryController.CategoryRuleDelete)
+
 	// User routes
 	v1.Get("/user", userController.UserFindAll)
 	v1.Get("/user/:id", userController.UserFindByID)